@@ -0,0 +1,172 @@
+package taint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/picatz/taint/callgraphutil"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// AnalyzeSource runs the given rules against a single Go source file given
+// as a string, which must be a main package. This is useful for testing rules,
+// or for playground-style tooling, without needing a module on disk.
+//
+// The source is loaded using a packages overlay in an otherwise empty module,
+// so it may only import packages from the standard library.
+func AnalyzeSource(src string, rules ...Rule) (Results, error) {
+	dir, err := os.MkdirTemp("", "taint-source-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary module: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module main\n"), 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary module: %w", err)
+	}
+
+	loadMode :=
+		packages.NeedName |
+			packages.NeedDeps |
+			packages.NeedFiles |
+			packages.NeedModule |
+			packages.NeedTypes |
+			packages.NeedImports |
+			packages.NeedSyntax |
+			packages.NeedTypesInfo
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:    loadMode,
+		Context: context.Background(),
+		Env:     os.Environ(),
+		Dir:     dir,
+		Overlay: map[string][]byte{
+			filepath.Join(dir, "main.go"): []byte(src),
+		},
+	}, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load source: %w", err)
+	}
+
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("failed to load source: %w", pkg.Errors[0])
+		}
+	}
+
+	_, ssaPkgs := ssautil.Packages(pkgs, ssa.InstantiateGenerics)
+
+	mainPkgs := ssautil.MainPackages(ssaPkgs)
+	if len(mainPkgs) == 0 {
+		return nil, fmt.Errorf("no main package found in source")
+	}
+
+	mainFn := mainPkgs[0].Func("main")
+	if mainFn == nil {
+		return nil, fmt.Errorf("no main function found in source")
+	}
+
+	return RunProgram(mainFn.Prog, []*ssa.Function{mainFn}, rules...)
+}
+
+// RunProgram runs the given rules against an existing SSA program, which is
+// useful for tools that already build SSA, without loading packages again.
+//
+// A callgraph is constructed for each of the given roots (e.g. main functions),
+// using the functions of the packages containing the roots as the source
+// functions. The program is built if it hasn't been already. Results found
+// from multiple roots are only included once. If analyzing any functions
+// panicked, they're skipped, and returned as *callgraphutil.PanicError errors
+// along with the results found for the rest of the program.
+func RunProgram(prog *ssa.Program, roots []*ssa.Function, rules ...Rule) (Results, error) {
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no root functions given")
+	}
+
+	prog.Build()
+
+	results := Results{}
+	reported := map[runResult]struct{}{}
+
+	// Panics recovered while building the callgraphs, or checking their
+	// paths, which are returned along with the results for the rest of
+	// the program.
+	var panics []error
+
+	for _, root := range roots {
+		if root == nil {
+			return nil, fmt.Errorf("nil root function given")
+		}
+
+		cg, err := callgraphutil.NewGraph(root, srcFuncs([]*ssa.Package{root.Pkg})...)
+		if err != nil {
+			var panicErr *callgraphutil.PanicError
+			if !errors.As(err, &panicErr) {
+				return nil, fmt.Errorf("failed to create new callgraph for %v: %w", root, err)
+			}
+			panics = append(panics, err)
+		}
+
+		runResults, err := RunContext(context.Background(), cg, rules...)
+		if err != nil {
+			panics = append(panics, err)
+		}
+
+		for _, result := range runResults {
+			key := runResult{rule: result.Rule, sink: result.SinkValue, source: result.SourceValue}
+			if _, ok := reported[key]; ok {
+				continue
+			}
+			reported[key] = struct{}{}
+
+			results = append(results, result)
+		}
+	}
+
+	SortResults(results)
+
+	return results, errors.Join(panics...)
+}
+
+// runResult identifies a result of a rule, to only include it once when
+// running the rule with multiple roots.
+type runResult struct {
+	rule   string
+	sink   ssa.Value
+	source ssa.Value
+}
+
+// srcFuncs returns all of the functions declared in the given packages,
+// including their anonymous functions.
+func srcFuncs(ssaPkgs []*ssa.Package) []*ssa.Function {
+	var fns []*ssa.Function
+
+	var addAnons func(f *ssa.Function)
+	addAnons = func(f *ssa.Function) {
+		fns = append(fns, f)
+		for _, anon := range f.AnonFuncs {
+			addAnons(anon)
+		}
+	}
+
+	for _, pkg := range ssaPkgs {
+		if pkg == nil {
+			continue
+		}
+		for _, member := range pkg.Members {
+			fn, ok := member.(*ssa.Function)
+			if !ok {
+				continue
+			}
+			addAnons(fn)
+		}
+	}
+
+	return fns
+}
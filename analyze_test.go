@@ -0,0 +1,112 @@
+package taint_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/picatz/taint"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+func TestAnalyzeSource(t *testing.T) {
+	src := `package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+func main() {
+	db, _ := sql.Open("sqlite3", ":memory:")
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		db.Query("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'")
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
+`
+
+	sqli := taint.NewRule(
+		"sqli",
+		"potential sql injection",
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+		nil,
+	)
+
+	results, err := taint.AnalyzeSource(src, sqli)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %v", len(results), results)
+	}
+
+	if results[0].Rule != "sqli" {
+		t.Errorf("expected result rule %q, got %q", "sqli", results[0].Rule)
+	}
+}
+
+func TestAnalyzeSourceError(t *testing.T) {
+	_, err := taint.AnalyzeSource("package main\n\nfunc main() {")
+	if err == nil {
+		t.Fatal("expected error for invalid source")
+	}
+}
+
+func TestRunProgram(t *testing.T) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:    packages.LoadAllSyntax,
+		Context: context.Background(),
+		Dir:     filepath.Join("testdata", "sqli"),
+	}, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Build the SSA program like a tool would, before running the rules.
+	ssaProg, ssaPkgs := ssautil.Packages(pkgs, ssa.InstantiateGenerics)
+	ssaProg.Build()
+
+	mainPkgs := ssautil.MainPackages(ssaPkgs)
+	if len(mainPkgs) == 0 {
+		t.Fatal("no main package found in testdata/sqli")
+	}
+
+	mainFn := mainPkgs[0].Func("main")
+
+	sqli := taint.NewRule(
+		"sqli",
+		"potential sql injection",
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+		nil,
+	)
+
+	// Giving the same root twice must not duplicate results.
+	results, err := taint.RunProgram(ssaProg, []*ssa.Function{mainFn, mainFn}, sqli)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cgResults := taint.Run(loadCallGraph(t, "sqli"), sqli)
+
+	if len(results) == 0 || len(results) != len(cgResults) {
+		t.Fatalf("expected %d results, got %d: %v", len(cgResults), len(results), results)
+	}
+
+	for _, result := range results {
+		if result.Rule != "sqli" {
+			t.Errorf("expected result rule %q, got %q", "sqli", result.Rule)
+		}
+	}
+
+	if _, err := taint.RunProgram(ssaProg, nil, sqli); err == nil {
+		t.Fatal("expected error for no roots")
+	}
+}
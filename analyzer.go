@@ -0,0 +1,30 @@
+package taint
+
+import (
+	"flag"
+	"go/types"
+)
+
+// RegisterFlags registers the -sources, -sinks, and -sanitizers flags on the
+// given flag set (e.g. an analyzer's Flags), adding to the given sets, which
+// allows configuring an analyzer without recompiling it, such as with
+// -sinks="(*example.com/db.Conn).Exec".
+func RegisterFlags(flags *flag.FlagSet, sources *Sources, sinks *Sinks, sanitizers *Sanitizers) {
+	flags.Var(sources, "sources", "comma-separated list of additional sources")
+	flags.Var(sinks, "sinks", "comma-separated list of additional sinks")
+	flags.Var(sanitizers, "sanitizers", "comma-separated list of additional sanitizers")
+}
+
+// Imports returns true if the given package directly imports any of the
+// given packages, comparing their exact import paths. Analyzers use it to
+// skip packages which can't call any of their sinks.
+func Imports(pkg *types.Package, paths ...string) bool {
+	for _, imp := range pkg.Imports() {
+		for _, path := range paths {
+			if imp.Path() == path {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package taint_test
+
+import (
+	"flag"
+	"go/types"
+	"testing"
+
+	"github.com/picatz/taint"
+)
+
+func TestImports(t *testing.T) {
+	pkg := types.NewPackage("example.com/main", "main")
+	pkg.SetImports([]*types.Package{
+		types.NewPackage("github.com/foo/chaos", "chaos"),
+		types.NewPackage("os/exec", "exec"),
+	})
+
+	if !taint.Imports(pkg, "os", "os/exec") {
+		t.Error("expected os/exec to be imported")
+	}
+
+	// Import paths ending in a given path aren't imports of it.
+	if taint.Imports(pkg, "os", "exec") {
+		t.Error("expected os not to be imported")
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	var (
+		sources    taint.Sources
+		sinks      taint.Sinks
+		sanitizers taint.Sanitizers
+	)
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	taint.RegisterFlags(flags, &sources, &sinks, &sanitizers)
+
+	err := flags.Parse([]string{"-sinks", "(*example.com/db.Conn).Exec,example.com/db.Exec", "-sanitizers", "html.EscapeString"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sources) != 0 || len(sinks) != 2 || len(sanitizers) != 1 {
+		t.Fatalf("expected 0 sources, 2 sinks and 1 sanitizer, got %v, %v and %v", sources, sinks, sanitizers)
+	}
+}
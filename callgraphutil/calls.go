@@ -1,6 +1,10 @@
 package callgraphutil
 
-import "golang.org/x/tools/go/callgraph"
+import (
+	"sort"
+
+	"golang.org/x/tools/go/callgraph"
+)
 
 // CalleesOf returns nodes that are called by the caller node.
 func CalleesOf(caller *callgraph.Node) Nodes {
@@ -33,3 +37,69 @@ func CallersOf(callee *callgraph.Node) Nodes {
 
 	return callersSlice
 }
+
+// Callees returns the nodes directly called by the nodes with a function
+// matching the given pattern, which is either an exact function name, or
+// a glob where "*" matches any characters, e.g. "main.handler*".
+//
+// The returned nodes are unique, and sorted by their function name.
+func Callees(cg *callgraph.Graph, pattern string) Nodes {
+	uniqCallees := make(map[*callgraph.Node]bool)
+	for fn, n := range cg.Nodes {
+		if fn == nil || !matchFunc(pattern, fn.String()) {
+			continue
+		}
+		for _, callee := range CalleesOf(n) {
+			uniqCallees[callee] = true
+		}
+	}
+
+	// Convert map to slice.
+	callees := make(Nodes, 0, len(uniqCallees))
+	for callee := range uniqCallees {
+		callees = append(callees, callee)
+	}
+
+	sort.Slice(callees, func(i, j int) bool {
+		return callees[i].Func.String() < callees[j].Func.String()
+	})
+
+	return callees
+}
+
+// Reachable returns the nodes reachable from the nodes with a function
+// matching the given pattern (see Callees), including the matching nodes
+// themselves, such as all of the functions called by an HTTP handler.
+//
+// The returned nodes are unique, and sorted by their function name.
+func Reachable(cg *callgraph.Graph, pattern string) Nodes {
+	var queue Nodes
+	for fn, n := range cg.Nodes {
+		if fn != nil && matchFunc(pattern, fn.String()) {
+			queue = append(queue, n)
+		}
+	}
+
+	seen := make(map[*callgraph.Node]bool)
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		queue = append(queue, CalleesOf(n)...)
+	}
+
+	// Convert map to slice.
+	reachable := make(Nodes, 0, len(seen))
+	for n := range seen {
+		reachable = append(reachable, n)
+	}
+
+	sort.Slice(reachable, func(i, j int) bool {
+		return reachable[i].Func.String() < reachable[j].Func.String()
+	})
+
+	return reachable
+}
@@ -0,0 +1,92 @@
+package callgraphutil_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/picatz/taint/callgraphutil"
+)
+
+func TestCallees(t *testing.T) {
+	ctx := context.Background()
+
+	pkgs, err := loadPackages(ctx, "./testdata/handler", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mainFn, srcFns, err := loadSSA(ctx, pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cg, err := loadCallGraph(ctx, mainFn, srcFns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pattern := range []string{
+		"github.com/picatz/taint/callgraphutil/testdata/handler.handler",
+		"*.handler",
+	} {
+		callees := callgraphutil.Callees(cg, pattern)
+
+		var found bool
+		for _, callee := range callees {
+			if callee.Func.Name() == "business" {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Errorf("expected callees of %q to include business, got %v", pattern, callees)
+		}
+	}
+
+	if callees := callgraphutil.Callees(cg, "main.missing"); len(callees) != 0 {
+		t.Errorf("expected no callees for a missing function, got %v", callees)
+	}
+}
+
+func TestReachable(t *testing.T) {
+	ctx := context.Background()
+
+	pkgs, err := loadPackages(ctx, "./testdata/handler", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mainFn, srcFns, err := loadSSA(ctx, pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cg, err := loadCallGraph(ctx, mainFn, srcFns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reachable := map[string]bool{}
+	for _, n := range callgraphutil.Reachable(cg, "*.handler") {
+		reachable[n.Func.String()] = true
+	}
+
+	// The handler itself, and the functions it calls, directly or not.
+	for _, fn := range []string{
+		"github.com/picatz/taint/callgraphutil/testdata/handler.handler",
+		"github.com/picatz/taint/callgraphutil/testdata/handler.business",
+		"fmt.Sprintf",
+	} {
+		if !reachable[fn] {
+			t.Errorf("expected %s to be reachable from the handler", fn)
+		}
+	}
+
+	if reachable["github.com/picatz/taint/callgraphutil/testdata/handler.main"] {
+		t.Error("expected main not to be reachable from the handler")
+	}
+
+	if nodes := callgraphutil.Reachable(cg, "main.missing"); len(nodes) != 0 {
+		t.Errorf("expected no nodes reachable from a missing function, got %v", nodes)
+	}
+}
@@ -2,12 +2,16 @@ package callgraphutil
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"go/token"
 	"go/types"
+	"sort"
 
 	"golang.org/x/tools/go/callgraph"
 	"golang.org/x/tools/go/ssa"
 	"golang.org/x/tools/go/ssa/ssautil"
+	"golang.org/x/tools/go/types/typeutil"
 )
 
 // GraphString returns a string representation of the call graph,
@@ -37,9 +41,25 @@ func GraphString(g *callgraph.Graph) string {
 // by traversing the SSA IR and adding edges to the graph; it handles calls
 // to functions, methods, closures, and interfaces. It may miss some complex
 // edges today, such as stucts containing function fields accessed via slice or map
-// indexing. This is a known limitation, but something we hope to improve in the near future.
+// indexing, although calls to functions stored in struct fields are linked to each
+// function stored in that field (e.g. cmd.run(args)). This is a known limitation, but something we hope to improve in the near future.
 // https://github.com/picatz/taint/issues/23
 func NewGraph(root *ssa.Function, srcFns ...*ssa.Function) (*callgraph.Graph, error) {
+	return NewGraphWithOptions(root, GraphOptions{}, srcFns...)
+}
+
+// GraphOptions are used to configure how a Graph is constructed.
+type GraphOptions struct {
+	// Progress is called after each source function is added to the
+	// graph, with the number of source functions processed so far, the
+	// total number of source functions, and the number of nodes in the
+	// graph, which can be used to report progress for large programs.
+	Progress func(done, total, nodes int)
+}
+
+// NewGraphWithOptions returns a new Graph with the specified root node,
+// like NewGraph, using the given options.
+func NewGraphWithOptions(root *ssa.Function, opts GraphOptions, srcFns ...*ssa.Function) (*callgraph.Graph, error) {
 	g := &callgraph.Graph{
 		Nodes: make(map[*ssa.Function]*callgraph.Node),
 	}
@@ -47,29 +67,175 @@ func NewGraph(root *ssa.Function, srcFns ...*ssa.Function) (*callgraph.Graph, er
 	g.Root = g.CreateNode(root)
 
 	allFns := ssautil.AllFunctions(root.Prog)
+	fields := newFieldIndex(allFns)
 
-	for _, srcFn := range srcFns {
+	// Panics recovered while adding individual source functions, which
+	// are returned to the caller once the rest of the graph is built.
+	var panics []error
+
+	for i, srcFn := range srcFns {
 		// debug("adding src function %d/%d: %v\n", i+1, len(srcFns), srcFn)
 
-		err := AddFunction(g, srcFn, allFns)
+		err := addSrcFunction(root, allFns, fields, g, srcFn, func() {
+			if opts.Progress != nil {
+				opts.Progress(i+1, len(srcFns), len(g.Nodes))
+			}
+		})
 		if err != nil {
+			var panicErr *PanicError
+			if errors.As(err, &panicErr) {
+				panics = append(panics, err)
+				continue
+			}
 			return g, fmt.Errorf("failed to add src function %v: %w", srcFn, err)
 		}
+	}
 
-		for _, block := range srcFn.DomPreorder() {
-			for _, instr := range block.Instrs {
-				checkBlockInstruction(root, allFns, g, srcFn, instr)
-			}
+	NormalizeIDs(g)
+
+	return g, errors.Join(panics...)
+}
+
+// NewMultiRootGraph returns a new Graph for a program without a single
+// root function, such as a library, which has many entry points (e.g. each
+// exported function), using the given options. The graph's root is a
+// synthetic function, which has an edge to each of the given roots, without
+// a call site.
+//
+// The roots and source functions are sorted before they're added, so the
+// resulting graph, including the order of its edges, is the same for the
+// same functions, regardless of the order they're given in.
+func NewMultiRootGraph(roots []*ssa.Function, opts GraphOptions, srcFns ...*ssa.Function) (*callgraph.Graph, error) {
+	if len(roots) == 0 {
+		return nil, errors.New("no root functions given")
+	}
+
+	roots, srcFns = sortedFunctions(roots), sortedFunctions(srcFns)
+
+	root := roots[0].Prog.NewFunction("<root>", types.NewSignatureType(nil, nil, nil, nil, nil, false), "multi-root")
+	// Share the first root's package, so interfaces declared within it
+	// can be resolved (see invokedFunction).
+	root.Pkg = roots[0].Pkg
+
+	g, err := NewGraphWithOptions(root, opts, srcFns...)
+	if g == nil {
+		return nil, err
+	}
+
+	for _, fn := range roots {
+		callgraph.AddEdge(g.Root, nil, g.CreateNode(fn))
+	}
+
+	NormalizeIDs(g)
+
+	return g, err
+}
+
+// sortedFunctions returns a sorted copy of the given functions, ordered
+// by their strings (e.g. "fmt.Println"), then their positions, since
+// synthetic functions may share the same string.
+func sortedFunctions(fns []*ssa.Function) []*ssa.Function {
+	sorted := make([]*ssa.Function, len(fns))
+	copy(sorted, fns)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		fi, fj := sorted[i], sorted[j]
+		if fi.String() != fj.String() {
+			return fi.String() < fj.String()
+		}
+		return fi.Pos() < fj.Pos()
+	})
+	return sorted
+}
+
+// PanicError is returned when analyzing a function panics, such as due to
+// an unexpected SSA form, which is recovered so the rest of the program can
+// still be analyzed.
+type PanicError struct {
+	// Func is the function being analyzed when the panic occurred.
+	Func *ssa.Function
+	// Value is the value the panic was called with.
+	Value any
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic while analyzing %v: %v", e.Func, e.Value)
+}
+
+// RecoverPanic recovers a panic while analyzing the given function, setting
+// the given error to a *PanicError. It must be called directly by a
+// deferred function.
+//
+//	defer callgraphutil.RecoverPanic(fn, &err)
+func RecoverPanic(fn *ssa.Function, err *error) {
+	if r := recover(); r != nil {
+		*err = &PanicError{Func: fn, Value: r}
+	}
+}
+
+// addSrcFunction adds the given source function to the graph, including
+// the edges for each of the calls it makes, then calls done. Any panic
+// is recovered, and returned as a *PanicError.
+func addSrcFunction(root *ssa.Function, allFns map[*ssa.Function]bool, fields *fieldIndex, g *callgraph.Graph, srcFn *ssa.Function, done func()) (err error) {
+	defer RecoverPanic(srcFn, &err)
+
+	if err := AddFunction(g, srcFn, allFns); err != nil {
+		return err
+	}
+
+	for _, block := range srcFn.DomPreorder() {
+		for _, instr := range block.Instrs {
+			checkBlockInstruction(root, allFns, fields, g, srcFn, instr)
 		}
 	}
 
-	return g, nil
+	done()
+
+	return nil
+}
+
+// NormalizeIDs reassigns the IDs of the nodes in the graph so they are
+// deterministic, instead of depending on the order nodes were created.
+// The root node is always assigned ID 0, and the remaining nodes are
+// numbered in order of their function strings (e.g. "fmt.Println").
+func NormalizeIDs(g *callgraph.Graph) {
+	nodes := make([]*callgraph.Node, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		if n == g.Root {
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		fi, fj := nodes[i].Func, nodes[j].Func
+		if fi.String() != fj.String() {
+			return fi.String() < fj.String()
+		}
+		// Synthetic functions may share the same string, so fall back
+		// to their position, then their signature.
+		if fi.Pos() != fj.Pos() {
+			return fi.Pos() < fj.Pos()
+		}
+		return fi.Signature.String() < fj.Signature.String()
+	})
+
+	id := 0
+	if g.Root != nil {
+		g.Root.ID = id
+		id++
+	}
+
+	for _, n := range nodes {
+		n.ID = id
+		id++
+	}
 }
 
 // checkBlockInstruction checks the given instruction for any function calls, adding
 // edges to the call graph as needed and recursively adding any new functions to the graph
 // that are discovered during the process (typically via interface methods).
-func checkBlockInstruction(root *ssa.Function, allFns map[*ssa.Function]bool, g *callgraph.Graph, fn *ssa.Function, instr ssa.Instruction) error {
+func checkBlockInstruction(root *ssa.Function, allFns map[*ssa.Function]bool, fields *fieldIndex, g *callgraph.Graph, fn *ssa.Function, instr ssa.Instruction) error {
 	// debug("\tcheckBlockInstruction: %v\n", instr)
 	switch instrt := instr.(type) {
 	case *ssa.Call:
@@ -164,31 +330,41 @@ func checkBlockInstruction(root *ssa.Function, allFns map[*ssa.Function]bool, g
 			// get the function from the method receiver which
 			// is not available directly from the call instruction,
 			// but rather from the package level function.
-
-			// Skip this instruction if we could not determine
-			// the function being called.
-			if !instrt.Call.IsInvoke() || (instrt.Call.Method == nil) {
-				return nil
+			instrCall = invokedFunction(root, &instrt.Call)
+		default:
+			// Interface method calls on other values, such as an interface
+			// loaded from a closure's free variable, are handled the same
+			// way as calls on parameters.
+			//
+			//  logger := log.NewLogfmtLogger(os.Stderr)
+			//
+			//  http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			//  	logger.Log("input", r.URL.Query().Get("input"))
+			//  })
+			//
+			instrCall = invokedFunction(root, &instrt.Call)
+			if instrCall != nil {
+				break
 			}
 
-			// TODO: should we share the resulting function?
-			instrtCallMethodPkg := instrt.Call.Method.Pkg()
-			if instrtCallMethodPkg == nil {
-				// This is an interface method call from the universe scope, such as "error.Error",
-				// so we return nil to skip this instruction, which we will assume is safe.
-				return nil
-			} else {
-				pkg := root.Prog.ImportedPackage(instrt.Call.Method.Pkg().Path())
-
-				fn := pkg.Func(instrt.Call.Method.Name())
-				if fn == nil {
-					fn = pkg.Prog.NewFunction(instrt.Call.Method.Name(), instrt.Call.Signature(), "callgraph")
+			// Calls to functions stored in a struct field are linked to each
+			// function stored in that field of the struct type, anywhere in
+			// the program, since the field may be set in another function.
+			//
+			//  type command struct {
+			//  	run func(args []string)
+			//  }
+			//
+			//  cmd.run(args) // calls each function stored in command.run
+			//
+			for _, target := range fields.functions(instrt.Call.Value) {
+				callgraph.AddEdge(g.CreateNode(fn), instrt, g.CreateNode(target))
+
+				err := AddFunction(g, target, allFns)
+				if err != nil {
+					return fmt.Errorf("failed to add function %v from struct field: %w", target, err)
 				}
-				instrCall = fn
 			}
-		default:
-			// case *ssa.TypeAssert: ??
-			// fmt.Printf("unknown call type: %v: %[1]T\n", callt)
 		}
 
 		// If we could not determine the function being
@@ -197,6 +373,8 @@ func checkBlockInstruction(root *ssa.Function, allFns map[*ssa.Function]bool, g
 			return nil
 		}
 
+		_, seen := g.Nodes[instrCall]
+
 		callgraph.AddEdge(g.CreateNode(fn), instrt, g.CreateNode(instrCall))
 
 		err := AddFunction(g, instrCall, allFns)
@@ -204,6 +382,24 @@ func checkBlockInstruction(root *ssa.Function, allFns map[*ssa.Function]bool, g
 			return fmt.Errorf("failed to add function %v from block instr: %w", instrCall, err)
 		}
 
+		// Follow synthetic wrapper functions, such as bound method closures,
+		// method expression thunks, and promoted method wrappers for embedded
+		// fields, which are not source functions, to the method they wrap.
+		//
+		//  type store struct {
+		//  	*sql.DB
+		//  }
+		//
+		//  (*store).Query(s, q) // calls (*store).Query$thunk → (*store).Query → (*database/sql.DB).Query
+		//
+		if !seen && instrCall.Synthetic != "" {
+			for _, block := range instrCall.Blocks {
+				for _, instr := range block.Instrs {
+					checkBlockInstruction(root, allFns, fields, g, instrCall, instr)
+				}
+			}
+		}
+
 		// attempt to link function arguments that are functions
 		for a := 0; a < len(instrt.Call.Args); a++ {
 			arg := instrt.Call.Args[a]
@@ -221,12 +417,13 @@ func checkBlockInstruction(root *ssa.Function, allFns map[*ssa.Function]bool, g
 	}
 
 	// Delete duplicate edges that may have been added, which is a responsibility of the caller
-	// when using the callgraph.AddEdge function directly.
+	// when using the callgraph.AddEdge function directly. Edges to the same callee from
+	// different call sites are kept, since each call may be given different arguments.
 	for _, n := range g.Nodes {
 		// debug("checking node %v\n", n)
 		for i := 0; i < len(n.Out); i++ {
 			for j := i + 1; j < len(n.Out); j++ {
-				if n.Out[i].Callee == n.Out[j].Callee {
+				if n.Out[i].Callee == n.Out[j].Callee && n.Out[i].Site == n.Out[j].Site {
 					// debug("deleting duplicate edge %v\n", n.Out[j])
 					n.Out = append(n.Out[:j], n.Out[j+1:]...)
 					j--
@@ -238,6 +435,159 @@ func checkBlockInstruction(root *ssa.Function, allFns map[*ssa.Function]bool, g
 	return nil
 }
 
+// invokedFunction returns the function for the interface method called by
+// the given "invoke" mode call, which is either the package level function
+// of the same name, or a new synthetic function with the method's signature.
+// It returns nil if the call is not an interface method call, or if the
+// method is from the universe scope, such as "error.Error", which we will
+// assume is safe.
+func invokedFunction(root *ssa.Function, call *ssa.CallCommon) *ssa.Function {
+	if !call.IsInvoke() || call.Method == nil || call.Method.Pkg() == nil {
+		return nil
+	}
+
+	// TODO: should we share the resulting function?
+	pkg := root.Prog.ImportedPackage(call.Method.Pkg().Path())
+	if pkg == nil && root.Pkg != nil && root.Pkg.Pkg == call.Method.Pkg() {
+		// Interfaces declared in the root's package, which isn't importable
+		// when it is created by the analyzer (e.g. buildssa).
+		pkg = root.Pkg
+	}
+	if pkg == nil {
+		return nil
+	}
+
+	fn := pkg.Func(call.Method.Name())
+	if fn == nil {
+		fn = pkg.Prog.NewFunction(call.Method.Name(), call.Signature(), "callgraph")
+	}
+	return fn
+}
+
+// fieldIndex indexes the functions stored in each struct field, such as
+// the closure stored in cmd.run, by any function of the program. It's
+// built once per graph, on the first call through a struct field.
+type fieldIndex struct {
+	allFns map[*ssa.Function]bool
+
+	// fields maps each struct type to the functions stored
+	// in each of its fields, by field index.
+	fields *typeutil.Map
+}
+
+// newFieldIndex returns a new, unbuilt index of the given functions.
+func newFieldIndex(allFns map[*ssa.Function]bool) *fieldIndex {
+	return &fieldIndex{allFns: allFns}
+}
+
+// build indexes every function stored into a struct field.
+func (idx *fieldIndex) build() {
+	idx.fields = &typeutil.Map{}
+
+	for progFn := range idx.allFns {
+		for _, block := range progFn.Blocks {
+			for _, instr := range block.Instrs {
+				store, ok := instr.(*ssa.Store)
+				if !ok {
+					continue
+				}
+
+				fa, ok := store.Addr.(*ssa.FieldAddr)
+				if !ok {
+					continue
+				}
+
+				structType := pointerElem(fa.X.Type())
+				if structType == nil {
+					continue
+				}
+
+				var target *ssa.Function
+				switch val := store.Val.(type) {
+				case *ssa.Function:
+					target = val
+				case *ssa.MakeClosure:
+					target, _ = val.Fn.(*ssa.Function)
+				}
+				if target == nil {
+					continue
+				}
+
+				fields, _ := idx.fields.At(structType).(map[int][]*ssa.Function)
+				if fields == nil {
+					fields = map[int][]*ssa.Function{}
+					idx.fields.Set(structType, fields)
+				}
+				fields[fa.Field] = append(fields[fa.Field], target)
+			}
+		}
+	}
+
+	// Sort and deduplicate the functions, since they're found in map order.
+	idx.fields.Iterate(func(_ types.Type, v interface{}) {
+		fields := v.(map[int][]*ssa.Function)
+		for field, fns := range fields {
+			fields[field] = uniqueFunctions(sortedFunctions(fns))
+		}
+	})
+}
+
+// functions returns the functions stored in the struct field loaded by
+// the given value, such as cmd.run. It returns nil if the value is not
+// loaded from a struct field.
+func (idx *fieldIndex) functions(v ssa.Value) []*ssa.Function {
+	var (
+		structType types.Type
+		field      int
+	)
+
+	switch vt := v.(type) {
+	case *ssa.UnOp:
+		// Loaded from the address of a field, e.g. a pointer receiver.
+		fa, ok := vt.X.(*ssa.FieldAddr)
+		if !ok || vt.Op != token.MUL {
+			return nil
+		}
+		structType, field = pointerElem(fa.X.Type()), fa.Field
+	case *ssa.Field:
+		structType, field = vt.X.Type(), vt.Field
+	default:
+		return nil
+	}
+	if structType == nil {
+		return nil
+	}
+
+	if idx.fields == nil {
+		idx.build()
+	}
+
+	fields, _ := idx.fields.At(structType).(map[int][]*ssa.Function)
+	return fields[field]
+}
+
+// uniqueFunctions returns the given sorted functions, without duplicates.
+func uniqueFunctions(fns []*ssa.Function) []*ssa.Function {
+	unique := fns[:0]
+	for i, fn := range fns {
+		if i > 0 && fn == fns[i-1] {
+			continue
+		}
+		unique = append(unique, fn)
+	}
+	return unique
+}
+
+// pointerElem returns the element type of the given pointer type,
+// or nil if it is not a pointer.
+func pointerElem(t types.Type) types.Type {
+	ptr, ok := t.Underlying().(*types.Pointer)
+	if !ok {
+		return nil
+	}
+	return ptr.Elem()
+}
+
 // AddFunction analyzes the given target SSA function, adding information to the call graph.
 //
 // Based on the implementation of golang.org/x/tools/cmd/guru/callers.go:
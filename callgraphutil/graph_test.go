@@ -0,0 +1,213 @@
+package callgraphutil_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/picatz/taint/callgraphutil"
+	"golang.org/x/tools/go/callgraph"
+)
+
+func TestNewGraphWithOptionsProgress(t *testing.T) {
+	ctx := context.Background()
+
+	pkgs, err := loadPackages(ctx, "./testdata/mutual", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mainFn, srcFns, err := loadSSA(ctx, pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls, lastDone, lastNodes int
+
+	cg, err := callgraphutil.NewGraphWithOptions(mainFn, callgraphutil.GraphOptions{
+		Progress: func(done, total, nodes int) {
+			calls++
+			if done != lastDone+1 {
+				t.Errorf("expected done to be %d, got %d", lastDone+1, done)
+			}
+			if total != len(srcFns) {
+				t.Errorf("expected total to be %d, got %d", len(srcFns), total)
+			}
+			if nodes < lastNodes {
+				t.Errorf("expected nodes to not decrease, got %d after %d", nodes, lastNodes)
+			}
+			lastDone, lastNodes = done, nodes
+		},
+	}, srcFns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != len(srcFns) {
+		t.Fatalf("expected progress to be called %d times, got %d", len(srcFns), calls)
+	}
+
+	if lastNodes != len(cg.Nodes) {
+		t.Fatalf("expected %d nodes to be reported, got %d", len(cg.Nodes), lastNodes)
+	}
+}
+
+func TestNewGraphWithOptionsPanic(t *testing.T) {
+	ctx := context.Background()
+
+	pkgs, err := loadPackages(ctx, "./testdata/mutual", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mainFn, srcFns, err := loadSSA(ctx, pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(srcFns) < 2 {
+		t.Fatalf("expected at least 2 source functions, got %d", len(srcFns))
+	}
+
+	var calls int
+
+	cg, err := callgraphutil.NewGraphWithOptions(mainFn, callgraphutil.GraphOptions{
+		Progress: func(done, total, nodes int) {
+			calls++
+			// Panic while adding the first source function, which
+			// must not stop the others from being added.
+			if done == 1 {
+				panic("injected panic")
+			}
+		},
+	}, srcFns...)
+
+	var panicErr *callgraphutil.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected a panic error, got %v", err)
+	}
+
+	if panicErr.Func != srcFns[0] {
+		t.Errorf("expected panic for %v, got %v", srcFns[0], panicErr.Func)
+	}
+
+	if calls != len(srcFns) {
+		t.Fatalf("expected all %d source functions to be added, got %d", len(srcFns), calls)
+	}
+
+	if _, ok := cg.Nodes[srcFns[len(srcFns)-1]]; !ok {
+		t.Fatalf("expected %v to be in the graph", srcFns[len(srcFns)-1])
+	}
+}
+
+func TestNormalizeIDs(t *testing.T) {
+	ctx := context.Background()
+
+	build := func(reverse bool) []string {
+		pkgs, err := loadPackages(ctx, "./testdata/handler", ".")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mainFn, srcFns, err := loadSSA(ctx, pkgs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Reversing the source functions creates the nodes in a
+		// different order, which must not change their IDs.
+		if reverse {
+			for i, j := 0, len(srcFns)-1; i < j; i, j = i+1, j-1 {
+				srcFns[i], srcFns[j] = srcFns[j], srcFns[i]
+			}
+		}
+
+		cg, err := callgraphutil.NewGraph(mainFn, srcFns...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if cg.Root.ID != 0 {
+			t.Fatalf("expected root node to have ID 0, got %d", cg.Root.ID)
+		}
+
+		nodes := make([]string, 0, len(cg.Nodes))
+		for _, n := range cg.Nodes {
+			nodes = append(nodes, n.String())
+		}
+		sort.Strings(nodes)
+		return nodes
+	}
+
+	first, second := build(false), build(true)
+
+	if len(first) != len(second) {
+		t.Fatalf("expected %d nodes, got %d", len(first), len(second))
+	}
+
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("expected node %q, got %q", first[i], second[i])
+		}
+	}
+}
+
+func TestNewMultiRootGraphDeterministic(t *testing.T) {
+	ctx := context.Background()
+
+	build := func(reverse bool) []string {
+		pkgs, err := loadPackages(ctx, "./testdata/handler", ".")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, srcFns, err := loadSSA(ctx, pkgs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Reversing the roots and source functions must not change
+		// the graph, including the order of each node's edges.
+		if reverse {
+			for i, j := 0, len(srcFns)-1; i < j; i, j = i+1, j-1 {
+				srcFns[i], srcFns[j] = srcFns[j], srcFns[i]
+			}
+		}
+
+		cg, err := callgraphutil.NewMultiRootGraph(srcFns, callgraphutil.GraphOptions{}, srcFns...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		nodes := make([]*callgraph.Node, 0, len(cg.Nodes))
+		for _, n := range cg.Nodes {
+			nodes = append(nodes, n)
+		}
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+		var edges []string
+		for _, n := range nodes {
+			for _, e := range n.Out {
+				edges = append(edges, e.String())
+			}
+		}
+		return edges
+	}
+
+	first, second := build(false), build(true)
+
+	if len(first) == 0 {
+		t.Fatal("expected edges in the graph")
+	}
+
+	if len(first) != len(second) {
+		t.Fatalf("expected %d edges, got %d", len(first), len(second))
+	}
+
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("expected edge %d to be %q, got %q", i, first[i], second[i])
+		}
+	}
+}
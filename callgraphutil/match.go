@@ -0,0 +1,91 @@
+package callgraphutil
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/callgraph"
+)
+
+// Matcher reports whether a function string (e.g. "fmt.Println") matches.
+type Matcher func(fn string) bool
+
+// NewMatcher returns a Matcher for the given pattern, which uses one of
+// the following strategies, depending on the pattern's syntax:
+//
+//   - Regular expression, when the pattern is wrapped in slashes,
+//     e.g. "/^fmt\.Print(f|ln)$/".
+//   - Glob, when the pattern contains a "*", which matches any
+//     characters, e.g. "(*database/sql.DB).*".
+//   - Fuzzy, otherwise, where the characters of the pattern must
+//     appear in order (ignoring case), e.g. "sqlquery" matches
+//     "(*database/sql.DB).Query".
+func NewMatcher(pattern string) (Matcher, error) {
+	switch {
+	case len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/"):
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression pattern %q: %w", pattern, err)
+		}
+		return re.MatchString, nil
+	case strings.Contains(pattern, "*"):
+		re := globRegexp(pattern)
+		return re.MatchString, nil
+	default:
+		return func(fn string) bool {
+			return fuzzyMatch(pattern, fn)
+		}, nil
+	}
+}
+
+// FindNodes returns the nodes with a function matching the given
+// matcher, sorted by their function name.
+func FindNodes(cg *callgraph.Graph, match Matcher) Nodes {
+	var nodes Nodes
+	for fn, n := range cg.Nodes {
+		if fn == nil || !match(fn.String()) {
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Func.String() < nodes[j].Func.String()
+	})
+
+	return nodes
+}
+
+// matchFunc returns true if the given function string (e.g. "fmt.Println")
+// matches the pattern, which is either the exact function string, or a glob
+// where "*" matches any sequence of characters, e.g. "(*database/sql.DB).*".
+func matchFunc(pattern, fn string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == fn
+	}
+
+	return globRegexp(pattern).MatchString(fn)
+}
+
+// globRegexp returns a regular expression for the given glob pattern,
+// where "*" matches any sequence of characters.
+func globRegexp(pattern string) *regexp.Regexp {
+	return regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$")
+}
+
+// fuzzyMatch returns true if the characters of the pattern appear
+// in the given string in order, ignoring case.
+func fuzzyMatch(pattern, s string) bool {
+	pattern, s = strings.ToLower(pattern), strings.ToLower(s)
+	for _, r := range pattern {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+utf8.RuneLen(r):]
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package callgraphutil_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/picatz/taint/callgraphutil"
+)
+
+func TestFindNodes(t *testing.T) {
+	ctx := context.Background()
+
+	pkgs, err := loadPackages(ctx, "./testdata/handler", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mainFn, srcFns, err := loadSSA(ctx, pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cg, err := loadCallGraph(ctx, mainFn, srcFns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{
+			pattern: `/handler\.(business|handler)$/`,
+			want: []string{
+				"github.com/picatz/taint/callgraphutil/testdata/handler.business",
+				"github.com/picatz/taint/callgraphutil/testdata/handler.handler",
+			},
+		},
+		{
+			pattern: "*testdata/handler.bus*",
+			want: []string{
+				"github.com/picatz/taint/callgraphutil/testdata/handler.business",
+			},
+		},
+		{
+			pattern: "handlerbusiness",
+			want: []string{
+				"github.com/picatz/taint/callgraphutil/testdata/handler.business",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			match, err := callgraphutil.NewMatcher(test.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nodes := callgraphutil.FindNodes(cg, match)
+
+			if len(nodes) != len(test.want) {
+				t.Fatalf("expected %d nodes, got %d: %v", len(test.want), len(nodes), nodes)
+			}
+
+			for i, n := range nodes {
+				if n.Func.String() != test.want[i] {
+					t.Errorf("expected node %d to be %q, got %q", i, test.want[i], n.Func.String())
+				}
+			}
+		})
+	}
+
+	if _, err := callgraphutil.NewMatcher("/(/"); err == nil {
+		t.Fatal("expected an error for an invalid regular expression")
+	}
+}
@@ -2,6 +2,7 @@ package callgraphutil
 
 import (
 	"bytes"
+	"context"
 
 	"golang.org/x/tools/go/callgraph"
 )
@@ -134,17 +135,30 @@ func PathSearch(start *callgraph.Node, isMatch func(*callgraph.Node) bool) Path
 //
 // To find the first path (which may not be the shortest), use PathSearch.
 func PathsSearch(start *callgraph.Node, isMatch func(*callgraph.Node) bool) Paths {
+	paths, _ := PathsSearchContext(context.Background(), start, isMatch)
+	return paths
+}
+
+// PathsSearchContext is like PathsSearch, but stops searching once the
+// given context is canceled, returning the paths found so far and the
+// context's error.
+func PathsSearchContext(ctx context.Context, start *callgraph.Node, isMatch func(*callgraph.Node) bool) (Paths, error) {
 	var (
 		paths = Paths{}
 
 		stack = make(Path, 0, 32)
 		seen  = make(map[*callgraph.Node]bool)
 
+		// Nodes on the current search path, which is used to prevent
+		// following cycles (e.g. recursive calls) forever, since the
+		// seen nodes are reset each time a path is found.
+		onStack = make(map[*callgraph.Node]bool)
+
 		search func(n *callgraph.Node)
 	)
 
 	search = func(n *callgraph.Node) {
-		if n == nil {
+		if n == nil || onStack[n] || ctx.Err() != nil {
 			return
 		}
 
@@ -152,32 +166,37 @@ func PathsSearch(start *callgraph.Node, isMatch func(*callgraph.Node) bool) Path
 		if !seen[n] {
 			seen[n] = true
 			if isMatch(n) {
-				paths = append(paths, stack)
+				// Copy the stack, since it continues to be used
+				// to find more paths with the same prefix.
+				path := make(Path, len(stack))
+				copy(path, stack)
+				paths = append(paths, path)
 
-				stack = make(Path, 0, 32)
 				seen = make(map[*callgraph.Node]bool)
 				return
 			}
+			onStack[n] = true
 			for _, e := range n.Out {
 				// debug("\tout: %v\n", e)
 				stack = append(stack, e) // push
 				search(e.Callee)
-				if len(stack) == 0 {
-					continue
-				}
 				stack = stack[:len(stack)-1] // pop
 			}
+			delete(onStack, n)
 		}
 	}
 	search(start)
 
-	return paths
+	return paths, ctx.Err()
 }
 
 // PathSearchCallTo returns the first path found from the start node
 // to a node that matches the function name.
 func PathSearchCallTo(start *callgraph.Node, fn string) Path {
 	return PathSearch(start, func(n *callgraph.Node) bool {
+		if n == nil || n.Func == nil {
+			return false
+		}
 		fnStr := n.Func.String()
 		return fnStr == fn
 	})
@@ -186,7 +205,14 @@ func PathSearchCallTo(start *callgraph.Node, fn string) Path {
 // PathsSearchCallTo returns the paths that call the given function name,
 // which uses SSA function name syntax, e.g.: "(*database/sql.DB).Query".
 func PathsSearchCallTo(start *callgraph.Node, fn string) Paths {
-	return PathsSearch(start, func(n *callgraph.Node) bool {
+	paths, _ := PathsSearchCallToContext(context.Background(), start, fn)
+	return paths
+}
+
+// PathsSearchCallToContext is like PathsSearchCallTo, but stops searching
+// once the given context is canceled, returning the context's error.
+func PathsSearchCallToContext(ctx context.Context, start *callgraph.Node, fn string) (Paths, error) {
+	return PathsSearchContext(ctx, start, func(n *callgraph.Node) bool {
 		if n == nil || n.Func == nil {
 			return false
 		}
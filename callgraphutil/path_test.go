@@ -0,0 +1,77 @@
+package callgraphutil_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/picatz/taint/callgraphutil"
+)
+
+func TestPathsSearchCallToMutualRecursion(t *testing.T) {
+	ctx := context.Background()
+
+	pkgs, err := loadPackages(ctx, "./testdata/mutual", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mainFn, srcFns, err := loadSSA(ctx, pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cg, err := loadCallGraph(ctx, mainFn, srcFns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := callgraphutil.PathsSearchCallTo(cg.Root, "fmt.Println")
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 paths, got %d: %v", len(paths), paths)
+	}
+
+	for _, path := range paths {
+		if path.First().Caller != cg.Root {
+			t.Errorf("expected path to start at root, got %v", path)
+		}
+		if path.Last().Callee.Func.String() != "fmt.Println" {
+			t.Errorf("expected path to end at fmt.Println, got %v", path)
+		}
+	}
+
+	path := callgraphutil.PathSearchCallTo(cg.Root, "fmt.Println")
+	if path.Empty() {
+		t.Fatal("expected a path to fmt.Println")
+	}
+}
+
+func TestPathsSearchCallToContextCanceled(t *testing.T) {
+	ctx := context.Background()
+
+	pkgs, err := loadPackages(ctx, "./testdata/mutual", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mainFn, srcFns, err := loadSSA(ctx, pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cg, err := loadCallGraph(ctx, mainFn, srcFns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	paths, err := callgraphutil.PathsSearchCallToContext(canceled, cg.Root, "fmt.Println")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the search to be canceled, got %v", err)
+	}
+	if len(paths) != 0 {
+		t.Fatalf("expected no paths, got %d: %v", len(paths), paths)
+	}
+}
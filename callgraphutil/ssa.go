@@ -8,8 +8,11 @@ import (
 // InstructionsFor returns the ssa.Instruction for the given ssa.Value using
 // the given node as the root of the call graph that is searched.
 func InstructionsFor(root *callgraph.Node, v ssa.Value) (si ssa.Instruction) {
-	PathsSearch(root, func(n *callgraph.Node) bool {
-		for _, b := range root.Func.Blocks {
+	PathSearch(root, func(n *callgraph.Node) bool {
+		if n.Func == nil {
+			return false
+		}
+		for _, b := range n.Func.Blocks {
 			for _, instr := range b.Instrs {
 				if instr.Pos() == v.Pos() {
 					si = instr
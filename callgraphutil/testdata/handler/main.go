@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func business(name string) string {
+	return fmt.Sprintf("hello %s", name)
+}
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(business(r.FormValue("name"))))
+}
+
+func main() {
+	http.HandleFunc("/", handler)
+	http.ListenAndServe(":8080", nil)
+}
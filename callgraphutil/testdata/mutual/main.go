@@ -0,0 +1,23 @@
+package main
+
+import "fmt"
+
+func ping(n int) {
+	if n == 0 {
+		fmt.Println("ping")
+		return
+	}
+	pong(n - 1)
+}
+
+func pong(n int) {
+	if n == 0 {
+		fmt.Println("pong")
+		return
+	}
+	ping(n - 1)
+}
+
+func main() {
+	ping(10)
+}
@@ -1,6 +1,17 @@
 package taint
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path"
+	"sort"
+
 	"golang.org/x/tools/go/callgraph"
 	"golang.org/x/tools/go/ssa"
 
@@ -26,6 +37,135 @@ type Result struct {
 	SinkType string
 	// Sink SSA value.
 	SinkValue ssa.Value
+
+	// SourceName is the source given to the check which matched, such as
+	// "*net/http.Request", or `os.Getenv("*_SECRET")`.
+	SourceName string
+	// SinkName is the sink given to the check which matched, such as
+	// "(*database/sql.DB).Query".
+	SinkName string
+
+	// EntryFunc is the function where the source value was introduced,
+	// such as the HTTP handler given the request (e.g. "main.handler"),
+	// which is useful to group results by entry point. It's empty if the
+	// source value isn't within a function, such as a global.
+	EntryFunc string
+
+	// Rule is the name of the rule that produced the result,
+	// which is only set when using Run.
+	Rule string
+	// Message is the rule's message describing the result,
+	// which is only set when using Run.
+	Message string
+
+	// Confidence is how likely the result is to be a true positive.
+	Confidence Confidence
+}
+
+// Confidence is how likely a result is to be a true positive.
+type Confidence string
+
+const (
+	// ConfidenceHigh results only flow through statically resolved calls.
+	ConfidenceHigh Confidence = "high"
+	// ConfidenceMedium results flow through dynamic calls (e.g. interface
+	// methods, or function values), which the callgraph may over-approximate.
+	ConfidenceMedium Confidence = "medium"
+)
+
+// pathConfidence returns the confidence of a result found using the given
+// path, which is lower if any of its calls are dynamic.
+func pathConfidence(path callgraphutil.Path) Confidence {
+	for _, edge := range path {
+		// Edges from a synthetic root (e.g. NewMultiRootGraph) have no site.
+		if edge.Site == nil {
+			continue
+		}
+		if edge.Site.Common().StaticCallee() == nil {
+			return ConfidenceMedium
+		}
+	}
+	return ConfidenceHigh
+}
+
+// Position returns the position of the sink call in the program's source,
+// which is invalid if the position isn't known (e.g. synthetic calls).
+func (r Result) Position() token.Position {
+	if r.SinkValue == nil || r.SinkValue.Parent() == nil {
+		return token.Position{}
+	}
+	return r.SinkValue.Parent().Prog.Fset.Position(r.SinkValue.Pos())
+}
+
+// SourcePosition returns the position of the source value in the program's
+// source, which is invalid if the position isn't known.
+func (r Result) SourcePosition() token.Position {
+	if r.SourceValue == nil || r.SourceValue.Parent() == nil {
+		return token.Position{}
+	}
+	return r.SourceValue.Parent().Prog.Fset.Position(r.SourceValue.Pos())
+}
+
+// Dedupe returns the given results without duplicates, which report the
+// same sink call tainted by the same source value, such as when combining
+// the results of multiple checks. The first of each duplicate is kept.
+func Dedupe(results Results) Results {
+	var (
+		deduped  Results
+		reported = map[sinkSource]struct{}{}
+	)
+	for _, result := range results {
+		if _, ok := reported[result.key()]; ok {
+			continue
+		}
+		reported[result.key()] = struct{}{}
+		deduped = append(deduped, result)
+	}
+	return deduped
+}
+
+// key returns the sink call and source value of the result, which is the
+// same for results found using different paths (e.g. recursion).
+func (r Result) key() sinkSource {
+	return sinkSource{site: r.Path.Last().Site, source: r.SourceValue}
+}
+
+// SortResults sorts the given results in a deterministic order, by their
+// rule, sink position, and source position, since the order results are
+// found in depends on the order the callgraph is searched.
+func SortResults(results Results) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rule != results[j].Rule {
+			return results[i].Rule < results[j].Rule
+		}
+		if c := comparePositions(results[i].Position(), results[j].Position()); c != 0 {
+			return c < 0
+		}
+		return comparePositions(results[i].SourcePosition(), results[j].SourcePosition()) < 0
+	})
+}
+
+// comparePositions compares two positions by their filename, line, and
+// column, returning -1, 0, or 1.
+func comparePositions(a, b token.Position) int {
+	switch {
+	case a.Filename != b.Filename:
+		if a.Filename < b.Filename {
+			return -1
+		}
+		return 1
+	case a.Line != b.Line:
+		if a.Line < b.Line {
+			return -1
+		}
+		return 1
+	case a.Column != b.Column:
+		if a.Column < b.Column {
+			return -1
+		}
+		return 1
+	}
+	return 0
 }
 
 // Results is a collection of unique findings from a taint check.
@@ -36,6 +176,10 @@ type Results []Result
 // It returns a list of results from the callgraph, where any of the given
 // sources found their way into any of the given sinks.
 //
+// Each sink call reached by a source is a distinct result, so a source
+// which flows into several sinks is reported once per sink, where each
+// result shares the same source value.
+//
 // Sources is a list of functions that return user-controlled values,
 // such as HTTP request parameters. Sinks is a list of potentially dangerous
 // functions that should not be called with user-controlled values.
@@ -60,18 +204,126 @@ type Results []Result
 // references and relevant SSA instructions to determine if any of the given
 // sinks were involved in the creation of the initial value.
 func Check(cg *callgraph.Graph, sources Sources, sinks Sinks) Results {
+	return CheckWithOptions(cg, sources, sinks, Options{})
+}
+
+// Options configures a taint check performed with CheckWithOptions.
+type Options struct {
+	// OnEdge is an optional callback invoked for each dataflow edge
+	// explored during the check, where taint may propagate from one
+	// SSA value to another. Unlike a result's Path, this includes
+	// the edges which do not lead to any results.
+	OnEdge func(from, to ssa.Value)
+
+	// Parameters are additional sources, such as the parameters of a
+	// library's exported functions, which are controlled by its callers
+	// (see LibraryEntryPoints).
+	Parameters []*ssa.Parameter
+
+	// Reflect enables a conservative heuristic for values set using
+	// reflection, e.g. reflect.ValueOf(&v).Elem().SetString(s), which
+	// taints all of v if s is tainted. This is approximate, since the
+	// exact field or element set is not known, so it's opt-in.
+	Reflect bool
+
+	// Sanitizers exclude results where the tainted value flowed through
+	// any of them before it reached the sink (e.g. html.EscapeString).
+	Sanitizers Sanitizers
+
+	// Scope, if set, only considers sources introduced within the given
+	// functions, such as those reachable from an entry point (see
+	// callgraphutil.Reachable). Sources found elsewhere are skipped,
+	// continuing the search for sources within the scope.
+	Scope map[*ssa.Function]bool
+}
+
+// CheckWithOptions is like Check, but configured with the given options.
+// Paths which panicked while being checked are skipped, use
+// CheckContextWithOptions to have them returned.
+func CheckWithOptions(cg *callgraph.Graph, sources Sources, sinks Sinks, opts Options) Results {
+	results, _ := check(context.Background(), cg, sources, sinks, opts)
+	return results
+}
+
+// CheckContext is like Check, but stops checking once the given context
+// is canceled, returning the results found so far and the context's error.
+// This is useful for long-running checks of large programs. Paths which
+// panicked while being checked are skipped, and returned as
+// *callgraphutil.PanicError errors, along with the other results.
+func CheckContext(ctx context.Context, cg *callgraph.Graph, sources Sources, sinks Sinks) (Results, error) {
+	return check(ctx, cg, sources, sinks, Options{})
+}
+
+// CheckContextWithOptions is like CheckContext, but configured with the
+// given options.
+func CheckContextWithOptions(ctx context.Context, cg *callgraph.Graph, sources Sources, sinks Sinks, opts Options) (Results, error) {
+	return check(ctx, cg, sources, sinks, opts)
+}
+
+// DebugOutput is where debug output is written when the TAINT_DEBUG
+// environment variable is set (e.g. TAINT_DEBUG=1), describing the sources
+// and sinks of each check, and each step taken to find taint. It's useful
+// to understand why a flow was (or wasn't) found.
+var DebugOutput io.Writer = os.Stderr
+
+// debugOutput returns DebugOutput if debug output is enabled by the
+// TAINT_DEBUG environment variable, or nil otherwise.
+func debugOutput() io.Writer {
+	switch os.Getenv("TAINT_DEBUG") {
+	case "", "0", "false":
+		return nil
+	}
+	return DebugOutput
+}
+
+// debugf writes a line of debug output to w, if it isn't nil.
+func debugf(w io.Writer, format string, args ...any) {
+	if w == nil {
+		return
+	}
+	fmt.Fprintf(w, "taint: "+format+"\n", args...)
+}
+
+func check(ctx context.Context, cg *callgraph.Graph, sources Sources, sinks Sinks, opts Options) (Results, error) {
 	// The results of the taint check.
 	results := Results{}
 
+	// Where debug output is written, if enabled.
+	debug := debugOutput()
+	if debug != nil {
+		for _, source := range sortedStrings(sources) {
+			debugf(debug, "source %s", source)
+		}
+		for _, param := range opts.Parameters {
+			debugf(debug, "source %s parameter %s", param.Parent(), param.Name())
+		}
+	}
+
+	// The sink calls and sources already reported in the results.
+	reported := map[sinkSource]struct{}{}
+
+	// Panics recovered while checking individual sink paths.
+	var panics []error
+
 	// For each sink given, identify the individual paths from
 	// within the callgraph that those sinks can end up as
 	// the final node path (the "sink path").
 	for sink := range sinks {
-		sinkPaths := callgraphutil.PathsSearchCallTo(cg.Root, sink)
+		sinkPaths, err := callgraphutil.PathsSearchCallToContext(ctx, cg.Root, sink)
+		if err != nil {
+			SortResults(results)
+			return results, errors.Join(append([]error{err}, panics...)...)
+		}
 
 		// fmt.Println("sink paths:", len(sinkPaths))
 
 		for _, sinkPath := range sinkPaths {
+			// Stop checking if the context was canceled.
+			if err := ctx.Err(); err != nil {
+				SortResults(results)
+				return results, errors.Join(append([]error{err}, panics...)...)
+			}
+
 			// fmt.Println("sink path:", sinkPath)
 			// Ensure the path isn't empty (which can happen?!).
 			//
@@ -84,33 +336,747 @@ func Check(cg *callgraph.Graph, sources Sources, sinks Sinks) Results {
 			// Check if the last edge (e.g. a SQL query) used any of the given
 			// sources (e.g. user input in an HTTP request) to identify if it
 			// was "tainted".
-			tainted, src, tv := checkPath(sinkPath, sources)
+			if debug != nil {
+				debugf(debug, "sink %s called at %s", sink, sinkPosition(sinkPath))
+			}
+
+			tainted, src, tv, err := checkSinkPath(sinkPath, sources, opts, debug)
+			if err != nil {
+				// Continue checking the other paths, returning the panic
+				// to the caller once finished.
+				panics = append(panics, err)
+				continue
+			}
 			if tainted {
 				// Extract the last edge from the last part of the path
 				// to include the calle as the sink in the result.
 				lastEdge := sinkPath.Last()
 
-				// Add the result to the list of results.
-				results = append(results, Result{
+				result := Result{
 					Path:        sinkPath,
 					SourceType:  src,
 					SourceValue: tv,
 					SinkType:    lastEdge.Callee.String(),
-					SinkValue:   lastEdge.Site.Value(),
-				})
+					SinkValue:   sinkSite(sinkPath).Value(),
+					SourceName:  src,
+					SinkName:    sink,
+					EntryFunc:   entryFunc(tv),
+					Confidence:  pathConfidence(sinkPath),
+				}
+
+				// Only report each sink call tainted by a source once, since
+				// multiple paths may lead to the same call (e.g. recursion).
+				if _, ok := reported[result.key()]; ok {
+					continue
+				}
+				reported[result.key()] = struct{}{}
+
+				if len(opts.Sanitizers) > 0 && sanitized(result, sources, opts.Sanitizers) {
+					continue
+				}
+
+				// Add the result to the list of results.
+				results = append(results, result)
 			}
 		}
 	}
 
-	// Return the results of the taint check.
-	return results
+	// Return the results of the taint check, in a deterministic order.
+	SortResults(results)
+
+	return results, errors.Join(panics...)
+}
+
+// checkSinkPath checks if the call at the end of the given sink path is
+// tainted by any of the given sources. Any panic is recovered, and returned
+// as a *callgraphutil.PanicError for the function calling the sink.
+func checkSinkPath(sinkPath callgraphutil.Path, sources Sources, opts Options, debug io.Writer) (tainted bool, src string, tv ssa.Value, err error) {
+	defer callgraphutil.RecoverPanic(sinkPath.Last().Caller.Func, &err)
+
+	c := &checker{
+		path:    sinkPath,
+		sources: sources,
+		opts:    opts,
+		debug:   debug,
+		steps:   map[callerValue]struct{}{},
+	}
+
+	tainted, src, tv = c.checkPath()
+	if tainted {
+		debugf(debug, "tainted by %s", src)
+	} else {
+		debugf(debug, "not tainted")
+	}
+	return tainted, src, tv, nil
+}
+
+// Tainted returns true if the given value, within the function calling the
+// last edge of the given path, comes from any of the given sources. This is
+// useful to check other values used along with a result's sink call, such
+// as an operand of one of its arguments.
+func Tainted(path callgraphutil.Path, v ssa.Value, sources Sources) (tainted bool) {
+	if path.Empty() || v == nil {
+		return false
+	}
+
+	defer func() {
+		// Values which can't be checked aren't considered tainted.
+		if r := recover(); r != nil {
+			tainted = false
+		}
+	}()
+
+	c := &checker{
+		path:    path,
+		sources: sources,
+		steps:   map[callerValue]struct{}{},
+	}
+
+	tainted, _, _ = c.checkSSAValue(v, valueSet{})
+	return tainted
+}
+
+// sinkPosition returns the position of the sink call at the end of the
+// given path, for debug output.
+func sinkPosition(path callgraphutil.Path) token.Position {
+	site := sinkSite(path)
+	if site == nil || site.Parent() == nil {
+		return token.Position{}
+	}
+	return site.Parent().Prog.Fset.Position(site.Pos())
+}
+
+// sortedStrings returns the strings in the given set, sorted.
+func sortedStrings(set stringSet) []string {
+	strs := make([]string, 0, len(set))
+	for str := range set {
+		strs = append(strs, str)
+	}
+	sort.Strings(strs)
+	return strs
+}
+
+// entryFunc returns the name of the function the given source value was
+// introduced in, or an empty string if it isn't within a function.
+func entryFunc(v ssa.Value) string {
+	if v == nil || v.Parent() == nil {
+		return ""
+	}
+	return v.Parent().String()
+}
+
+// sinkSite returns the call site of the sink at the end of the given path,
+// or, if the sink is called from a synthetic wrapper function (e.g. a bound
+// method closure, or a promoted method of an embedded field), the call site
+// of the wrapper in the nearest function written by the user, which has a
+// position in the source code to report.
+func sinkSite(path callgraphutil.Path) ssa.CallInstruction {
+	for i := len(path) - 1; i > 0; i-- {
+		if path[i].Caller.Func.Synthetic == "" {
+			return path[i].Site
+		}
+	}
+	return path[0].Site
+}
+
+// CheckFunc performs a taint check within a single function, without
+// building a whole-program callgraph, which is much faster for targeted
+// analysis. The callgraph used only includes the given function, and
+// the functions it calls directly (plus their direct calls) which can
+// be statically resolved.
+//
+// Because the callers of the function are not known, sources must be
+// found within the function itself, such as one of its parameters.
+func CheckFunc(fn *ssa.Function, sources Sources, sinks Sinks) Results {
+	cg := callgraph.New(fn)
+
+	for _, callee := range addStaticCallees(cg, cg.Root) {
+		addStaticCallees(cg, callee)
+	}
+
+	return Check(cg, sources, sinks)
+}
+
+// addStaticCallees adds an edge to the callgraph for each statically
+// resolvable call within the given node's function, returning the
+// callee nodes which were not already in the callgraph.
+func addStaticCallees(cg *callgraph.Graph, node *callgraph.Node) []*callgraph.Node {
+	var callees []*callgraph.Node
+
+	for _, block := range node.Func.Blocks {
+		for _, instr := range block.Instrs {
+			site, ok := instr.(ssa.CallInstruction)
+			if !ok {
+				continue
+			}
+
+			calleeFn := site.Common().StaticCallee()
+			if calleeFn == nil {
+				continue
+			}
+
+			_, seen := cg.Nodes[calleeFn]
+
+			callee := cg.CreateNode(calleeFn)
+			callgraph.AddEdge(node, site, callee)
+
+			if !seen {
+				callees = append(callees, callee)
+			}
+		}
+	}
+
+	return callees
+}
+
+// CheckWithSanitizers is like Check, but excludes results where the
+// tainted value flowed through any of the given sanitizers before it
+// reached the sink (e.g. html.EscapeString). Paths which panicked while
+// being checked are skipped, use CheckContextWithOptions to have them
+// returned.
+func CheckWithSanitizers(cg *callgraph.Graph, sources Sources, sinks Sinks, sanitizers Sanitizers) Results {
+	return CheckWithOptions(cg, sources, sinks, Options{Sanitizers: sanitizers})
+}
+
+// sanitized returns true if the tainted value of the given result, and any
+// other source values (e.g. the same request), only reach the arguments of
+// the sink call through any of the given sanitizers. Sanitizers given other
+// values, which happen to be used along with the tainted value, don't
+// sanitize it.
+//
+//	fmt.Sprintf("%s %d", r.FormValue("t"), strconv.Atoi(x)) // not sanitized
+//	fmt.Sprintf("%s %s", html.EscapeString(r.FormValue("a")), r.FormValue("b")) // not sanitized
+func sanitized(result Result, sources Sources, sanitizers Sanitizers) bool {
+	last := result.Path.Last()
+	if last.Site == nil || result.SourceValue == nil {
+		return false
+	}
+
+	flow := &sanitizerFlow{
+		path:       result.Path,
+		source:     result.SourceValue,
+		sources:    &checker{sources: sources},
+		sanitizers: sanitizers,
+		visited:    map[sanitizerStep]struct{}{},
+	}
+
+	for _, site := range sinkCalls(result.Path) {
+		common := site.Common()
+		if common.IsInvoke() {
+			flow.walk(common.Value, false)
+		}
+		for _, arg := range common.Args {
+			flow.walk(arg, false)
+		}
+	}
+
+	return flow.sanitized && !flow.unsanitized
+}
+
+// sinkCalls returns the calls of the sink at the end of the given path.
+// Edges to the methods of an interface argument (e.g. io.Writer.Write, and
+// then its implementations) have the site of the call given the argument,
+// which isn't within their caller, so the calls of the method made by the
+// function given the argument are used instead.
+func sinkCalls(path callgraphutil.Path) []ssa.CallInstruction {
+	site := path.Last().Site
+
+	var edge *callgraph.Edge
+	for i := len(path) - 1; i >= 0 && path[i].Site == site; i-- {
+		if path[i].Caller.Func != site.Parent() {
+			edge = path[i]
+		}
+	}
+	if edge == nil || edge.Caller.Func == nil {
+		return []ssa.CallInstruction{site}
+	}
+
+	var calls []ssa.CallInstruction
+	for _, block := range edge.Caller.Func.Blocks {
+		for _, instr := range block.Instrs {
+			call, ok := instr.(ssa.CallInstruction)
+			if !ok {
+				continue
+			}
+			common := call.Common()
+			if common.IsInvoke() && common.Method.Name() == edge.Callee.Func.Name() {
+				calls = append(calls, call)
+			}
+		}
+	}
+	if len(calls) == 0 {
+		return []ssa.CallInstruction{site}
+	}
+	return calls
+}
+
+// sanitizerFlow walks "backwards" from the arguments of a sink call to the
+// tainted value, through the operands used to create each value, and the
+// values stored to it, noting if it was reached through a sanitizer.
+type sanitizerFlow struct {
+	path       callgraphutil.Path
+	source     ssa.Value
+	sanitizers Sanitizers
+
+	// sources matches the other source values, which are tainted too.
+	sources *checker
+
+	visited map[sanitizerStep]struct{}
+
+	// sanitized and unsanitized are set once the tainted value is
+	// reached through (or without) a sanitizer, respectively.
+	sanitized, unsanitized bool
+}
+
+// sanitizerStep is a value walked by a sanitizerFlow, and whether
+// it was reached through a sanitizer.
+type sanitizerStep struct {
+	value   ssa.Value
+	through bool
+}
+
+// walk walks back from the given value, which was reached through a
+// sanitizer if through is true.
+func (f *sanitizerFlow) walk(v ssa.Value, through bool) {
+	if v == nil {
+		return
+	}
+
+	step := sanitizerStep{value: v, through: through}
+	if _, ok := f.visited[step]; ok {
+		return
+	}
+	f.visited[step] = struct{}{}
+
+	if v == f.source || f.isSource(v) {
+		if through {
+			f.sanitized = true
+		} else {
+			f.unsanitized = true
+		}
+		return
+	}
+
+	switch value := v.(type) {
+	case *ssa.Call:
+		if _, ok := f.sanitizers.includes(value.Call.Value.String()); ok {
+			through = true
+		}
+		if value.Call.IsInvoke() {
+			f.walk(value.Call.Value, through)
+		}
+		for _, arg := range value.Call.Args {
+			f.walk(arg, through)
+		}
+		return
+	case *ssa.Alloc, *ssa.FieldAddr, *ssa.IndexAddr:
+		// Fields and elements are also derived from the value they're within.
+		switch addr := value.(type) {
+		case *ssa.FieldAddr:
+			f.walk(addr.X, through)
+		case *ssa.IndexAddr:
+			f.walk(addr.X, through)
+		}
+
+		refs := value.Referrers()
+		if refs == nil {
+			return
+		}
+		for _, ref := range *refs {
+			switch ref := ref.(type) {
+			case *ssa.Store:
+				if ref.Addr == v {
+					f.walk(ref.Val, through)
+				}
+			case *ssa.IndexAddr:
+				// Elements stored to the value, such as variadic arguments.
+				if ref.X == v {
+					f.walk(ref, through)
+				}
+			case *ssa.FieldAddr:
+				if ref.X == v {
+					f.walk(ref, through)
+				}
+			}
+		}
+		return
+	case *ssa.Parameter:
+		// Follow the parameter to the argument given to it by the
+		// call along the path.
+		for _, arg := range f.pathArgs(value) {
+			f.walk(arg, through)
+		}
+		return
+	case *ssa.Const, *ssa.Function, *ssa.Global, *ssa.FreeVar:
+		return
+	}
+
+	instr, ok := v.(ssa.Instruction)
+	if !ok {
+		return
+	}
+
+	for _, opr := range instr.Operands(nil) {
+		if opr != nil {
+			f.walk(*opr, through)
+		}
+	}
+}
+
+// isSource returns true if the given value is a source, by its type
+// (e.g. *net/http.Request), or by calling a source function.
+func (f *sanitizerFlow) isSource(v ssa.Value) bool {
+	if _, ok := f.sources.sourceType(v.Type()); ok {
+		return true
+	}
+	if call, ok := v.(*ssa.Call); ok {
+		_, ok := f.sources.sourceCall(call.Common())
+		return ok
+	}
+	return false
+}
+
+// pathArgs returns the arguments given to the parameter by the calls to
+// its function along the path, skipping callbacks (e.g. http.HandleFunc),
+// which aren't given the parameter themselves.
+func (f *sanitizerFlow) pathArgs(param *ssa.Parameter) []ssa.Value {
+	fn := param.Parent()
+
+	index := -1
+	for i, p := range fn.Params {
+		if p == param {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil
+	}
+
+	var args []ssa.Value
+	for _, edge := range f.path {
+		if edge.Site == nil || edge.Callee.Func != fn {
+			continue
+		}
+
+		common := edge.Site.Common()
+		if callee := common.StaticCallee(); callee != nil && callee != fn {
+			continue
+		}
+
+		switch {
+		case common.IsInvoke() && index == 0:
+			args = append(args, common.Value)
+		case common.IsInvoke() && index-1 < len(common.Args):
+			args = append(args, common.Args[index-1])
+		case !common.IsInvoke() && index < len(common.Args):
+			args = append(args, common.Args[index])
+		}
+	}
+	return args
+}
+
+// checker holds the state of a taint check for a single sink path.
+type checker struct {
+	path    callgraphutil.Path
+	sources Sources
+	opts    Options
+
+	// debug output, if enabled (see DebugOutput).
+	debug io.Writer
+
+	// stack of values currently being checked, which is only
+	// maintained when the OnEdge option or debug output is used.
+	stack []ssa.Value
+
+	// steps taken from function parameters back to their callers.
+	steps map[callerValue]struct{}
+
+	// args used for the parameters of functions being checked through
+	// their results, which may not be part of the path (see checkResult).
+	args map[*ssa.Parameter]ssa.Value
+}
+
+// callerValue is a value paired with a callgraph node calling its function.
+type callerValue struct {
+	caller *callgraph.Node
+	value  ssa.Value
+}
+
+// sinkSource is a sink call site paired with the source value tainting it.
+type sinkSource struct {
+	site   ssa.CallInstruction
+	source ssa.Value
+}
+
+// decoders are functions which decode data into a target value, mapped
+// to the index of the target argument (including the receiver). The
+// decoded target is tainted if any of the other arguments are tainted.
+var decoders = map[string]int{
+	"encoding/json.Unmarshal":                                                    1,
+	"(*encoding/json.Decoder).Decode":                                            1,
+	"encoding/xml.Unmarshal":                                                     1,
+	"(*encoding/xml.Decoder).Decode":                                             1,
+	"google.golang.org/protobuf/encoding/protojson.Unmarshal":                    1,
+	"google.golang.org/protobuf/proto.Unmarshal":                                 1,
+	"(google.golang.org/protobuf/encoding/protojson.UnmarshalOptions).Unmarshal": 2,
+	// errors.As assigns the (possibly wrapped) error to its target, which
+	// is tainted if the error is, e.g. created using fmt.Errorf.
+	"errors.As": 1,
+}
+
+// checkDecodeTarget checks if the given value is the target of a decoder
+// call, possibly converted to an interface, where any of the decoder's
+// other arguments are tainted.
+//
+//	var req request
+//	json.NewDecoder(r.Body).Decode(&req) // req is tainted by r.Body
+func (c *checker) checkDecodeTarget(v ssa.Value, visited valueSet) (bool, string, ssa.Value) {
+	refs := v.Referrers()
+	if refs == nil {
+		return false, "", nil
+	}
+
+	for _, ref := range *refs {
+		target := v
+
+		if mi, ok := ref.(*ssa.MakeInterface); ok {
+			if mi.Referrers() == nil {
+				continue
+			}
+			target = mi
+			for _, ref := range *mi.Referrers() {
+				if tainted, src, tv := c.checkDecoderCall(ref, target, visited); tainted {
+					return true, src, tv
+				}
+			}
+			continue
+		}
+
+		if tainted, src, tv := c.checkDecoderCall(ref, target, visited); tainted {
+			return true, src, tv
+		}
+	}
+
+	return false, "", nil
+}
+
+// checkDecoderCall checks if the given instruction is a decoder call with
+// the given target argument, and if so, whether its other arguments are
+// tainted.
+func (c *checker) checkDecoderCall(instr ssa.Instruction, target ssa.Value, visited valueSet) (bool, string, ssa.Value) {
+	call, ok := instr.(*ssa.Call)
+	if !ok {
+		return false, "", nil
+	}
+
+	fn := call.Call.StaticCallee()
+	if fn == nil {
+		return false, "", nil
+	}
+
+	idx, ok := decoders[fn.String()]
+	if !ok || idx >= len(call.Call.Args) || call.Call.Args[idx] != target {
+		return false, "", nil
+	}
+
+	for i, arg := range call.Call.Args {
+		if i == idx {
+			continue
+		}
+		if tainted, src, tv := c.checkSSAValue(arg, visited); tainted {
+			return true, src, tv
+		}
+	}
+
+	return false, "", nil
+}
+
+// checkSourceTarget checks if the given memory is filled by a call to a
+// source, given as one of the call's arguments, possibly converted to an
+// interface, or within its variadic arguments. Such sources write their
+// data to the memory, instead of returning it, using the call itself as
+// the source value.
+//
+//	var name string
+//	rows.Scan(&name) // name is tainted, if (*database/sql.Rows).Scan is a source
+func (c *checker) checkSourceTarget(v ssa.Value) (bool, string, ssa.Value) {
+	for _, call := range argumentCalls(v) {
+		if src, ok := c.sourceCall(call.Common()); ok {
+			return true, src, call
+		}
+	}
+	return false, "", nil
+}
+
+// argumentCalls returns the calls given the value as an argument, directly,
+// converted to an interface, or stored in the slice of variadic arguments.
+func argumentCalls(v ssa.Value) []*ssa.Call {
+	var calls []*ssa.Call
+
+	refs := v.Referrers()
+	if refs == nil {
+		return nil
+	}
+
+	for _, ref := range *refs {
+		switch ref := ref.(type) {
+		case *ssa.Call:
+			// Skip calls using the value as the receiver.
+			if !ref.Call.IsInvoke() && ref.Call.Signature().Recv() != nil && ref.Call.Args[0] == v {
+				continue
+			}
+			calls = append(calls, ref)
+		case *ssa.MakeInterface:
+			calls = append(calls, argumentCalls(ref)...)
+		case *ssa.Store:
+			// Stored as an element of the variadic arguments array,
+			// which is sliced to be given to the call.
+			idx, ok := ref.Addr.(*ssa.IndexAddr)
+			if !ok || ref.Val != v || idx.X.Referrers() == nil {
+				continue
+			}
+			for _, ref := range *idx.X.Referrers() {
+				if slice, ok := ref.(*ssa.Slice); ok {
+					calls = append(calls, argumentCalls(slice)...)
+				}
+			}
+		}
+	}
+
+	return calls
+}
+
+// reflectSetters are the methods of reflect.Value which set the value,
+// mapped to the index of the argument being set (including the receiver).
+var reflectSetters = map[string]int{
+	"(reflect.Value).Set":          1,
+	"(reflect.Value).SetString":    1,
+	"(reflect.Value).SetBytes":     1,
+	"(reflect.Value).SetMapIndex":  2,
+	"(reflect.Value).SetIterValue": 1,
+}
+
+// reflectDerivers are the methods of reflect.Value which return a value
+// referring to (part of) the same memory as the receiver.
+var reflectDerivers = map[string]struct{}{
+	"(reflect.Value).Elem":            {},
+	"(reflect.Value).Field":           {},
+	"(reflect.Value).FieldByIndex":    {},
+	"(reflect.Value).FieldByName":     {},
+	"(reflect.Value).FieldByNameFunc": {},
+	"(reflect.Value).Index":           {},
+	"(reflect.Value).Addr":            {},
+}
+
+// checkReflectTarget checks if the memory allocated by the given value
+// was set using reflection to a tainted value (see Options.Reflect).
+//
+//	var name string
+//	reflect.ValueOf(&name).Elem().SetString(r.FormValue("name")) // name is tainted
+func (c *checker) checkReflectTarget(alloc *ssa.Alloc, visited valueSet) (bool, string, ssa.Value) {
+	// Find the reflect.Value of the memory, created by reflect.ValueOf.
+	var values []ssa.Value
+	for _, ref := range *alloc.Referrers() {
+		mi, ok := ref.(*ssa.MakeInterface)
+		if !ok || mi.Referrers() == nil {
+			continue
+		}
+		for _, ref := range *mi.Referrers() {
+			call, ok := ref.(*ssa.Call)
+			if ok && call.Call.Value.String() == "reflect.ValueOf" {
+				values = append(values, call)
+			}
+		}
+	}
+
+	// Follow the values derived from it, until one of them is set.
+	seen := map[ssa.Value]struct{}{}
+	for len(values) > 0 {
+		v := values[len(values)-1]
+		values = values[:len(values)-1]
+		if _, ok := seen[v]; ok || v.Referrers() == nil {
+			continue
+		}
+		seen[v] = struct{}{}
+
+		for _, ref := range *v.Referrers() {
+			call, ok := ref.(*ssa.Call)
+			if !ok {
+				continue
+			}
+			args := call.Call.Args
+			if len(args) == 0 || args[0] != v {
+				continue
+			}
+			name := call.Call.Value.String()
+			if _, ok := reflectDerivers[name]; ok {
+				values = append(values, call)
+				continue
+			}
+			if i, ok := reflectSetters[name]; ok && i < len(args) {
+				tainted, src, tv := c.checkSSAValue(args[i], visited)
+				if tainted {
+					return true, src, tv
+				}
+			}
+		}
+	}
+
+	return false, "", nil
+}
+
+// sourceType returns the source for the given type, if it is a source,
+// either by name, or if the sources include ProtoMessageSource, by being
+// a protobuf message, such as a gRPC request.
+func (c *checker) sourceType(t types.Type) (string, bool) {
+	if src, ok := c.sources.includes(t.String()); ok {
+		return src, true
+	}
+
+	if _, ok := c.sources[ProtoMessageSource]; ok && protoMessage(t) {
+		return ProtoMessageSource, true
+	}
+
+	return "", false
+}
+
+// sourceCall returns the source for the given call, if it calls a source,
+// either by name (e.g. "os.Getenv"), or by name with a pattern matching its
+// first argument (e.g. `os.Getenv("*_SECRET")`), which must be a constant.
+func (c *checker) sourceCall(call *ssa.CallCommon) (string, bool) {
+	fn := call.Value.String()
+	if src, ok := c.sources.includes(fn); ok {
+		return src, true
+	}
+
+	if len(call.Args) == 0 {
+		return "", false
+	}
+
+	arg, ok := call.Args[0].(*ssa.Const)
+	if !ok || arg.Value == nil || arg.Value.Kind() != constant.String {
+		return "", false
+	}
+
+	for src := range c.sources {
+		srcFn, pattern, ok := argumentSource(src)
+		if !ok || srcFn != fn {
+			continue
+		}
+		if matched, _ := path.Match(pattern, constant.StringVal(arg.Value)); matched {
+			return src, true
+		}
+	}
+
+	return "", false
 }
 
 // checkPath implements taint analysis that can be used to identify if the given
 // callgraph path contains information from taintable sources (typically user input).
-func checkPath(path callgraphutil.Path, sources Sources) (bool, string, ssa.Value) {
+func (c *checker) checkPath() (bool, string, ssa.Value) {
 	// Ensure the path isn't empty (which can happen?!).
-	if path.Empty() {
+	if c.path.Empty() {
 		return false, "", nil
 	}
 
@@ -121,7 +1087,7 @@ func checkPath(path callgraphutil.Path, sources Sources) (bool, string, ssa.Valu
 
 	// Start at last call from the path to see if any of the given sources were used
 	// along with it to perform an action (e.g. SQL query).
-	tainted, src, tv := checkSSAValue(path, sources, path.Last().Site.Value(), visited)
+	tainted, src, tv := c.checkSSAValue(c.path.Last().Site.Value(), visited)
 	if tainted {
 		return true, src, tv
 	}
@@ -136,7 +1102,26 @@ func checkPath(path callgraphutil.Path, sources Sources) (bool, string, ssa.Valu
 // calls itself (or checkSSAInstruction) as nessecary.
 //
 // It returns true if the given SSA value is tained by any of the given sources.
-func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visited valueSet) (bool, string, ssa.Value) {
+func (c *checker) checkSSAValue(v ssa.Value, visited valueSet) (bool, string, ssa.Value) {
+	tainted, src, tv := c.checkValue(v, visited)
+	if tainted && !c.inScope(tv) {
+		return false, "", nil
+	}
+	return tainted, src, tv
+}
+
+// inScope returns true if the given source value was introduced within
+// the scope of the check, which includes every value if it isn't set.
+func (c *checker) inScope(v ssa.Value) bool {
+	if c.opts.Scope == nil {
+		return true
+	}
+	return v.Parent() != nil && c.opts.Scope[v.Parent()]
+}
+
+// checkValue checks if the given value comes from any of the sources,
+// regardless of the scope of the check (see checkSSAValue).
+func (c *checker) checkValue(v ssa.Value, visited valueSet) (bool, string, ssa.Value) {
 	// First, check if this value has already been visited.
 	//
 	// If so, we can assume it is safe.
@@ -149,6 +1134,20 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 	// calls from crashing the program.
 	visited.add(v)
 
+	// Notify the caller of the edge explored to reach this value,
+	// from the value that was being checked when it was reached.
+	if c.opts.OnEdge != nil || c.debug != nil {
+		if len(c.stack) > 0 {
+			to := c.stack[len(c.stack)-1]
+			if c.opts.OnEdge != nil {
+				c.opts.OnEdge(v, to)
+			}
+			debugf(c.debug, "  %s → %s", v, to)
+		}
+		c.stack = append(c.stack, v)
+		defer func() { c.stack = c.stack[:len(c.stack)-1] }()
+	}
+
 	// fmt.Printf("! check SSA value %s: %[1]T\n", v)
 
 	// This is the core of the algorithm.
@@ -175,26 +1174,41 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 	// because we need to step backwards through the callgraph path
 	// (just one step?) to identify what actual value the caller used.
 	case *ssa.Parameter:
+		// Check if the parameter itself is a source.
+		for _, param := range c.opts.Parameters {
+			if param == value {
+				return true, value.Parent().String() + " parameter " + value.Name(), value
+			}
+		}
+
 		// Check if the parameter's type is a source.
-		paramTypeStr := value.Type().String()
-		if src, ok := sources.includes(paramTypeStr); ok {
+		if src, ok := c.sourceType(value.Type()); ok {
 			return true, src, value
 		}
 
+		// Check the argument used for the parameter, if the function
+		// is being checked through one of its results.
+		if arg, ok := c.args[value]; ok {
+			tainted, src, tv := c.checkSSAValue(arg, visited)
+			if tainted {
+				return true, src, tv
+			}
+		}
+
 		// Check the parameter's referrers.
 		refs := value.Referrers()
 		if refs != nil {
 			for _, ref := range *refs {
 				refVal, isVal := ref.(ssa.Value)
 				if isVal {
-					tainted, src, tv := checkSSAValue(path, sources, refVal, visited)
+					tainted, src, tv := c.checkSSAValue(refVal, visited)
 					if tainted {
 						return true, src, tv
 					}
 					continue
 				}
 
-				tainted, src, tv := checkSSAInstruction(path, sources, ref, visited)
+				tainted, src, tv := c.checkSSAInstruction(ref, visited)
 				if tainted {
 					return true, src, tv
 				}
@@ -203,9 +1217,18 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 
 		// TODO: consider if we can remove the range with a single
 		//       step backwards?
-		for _, edge := range path {
+		for _, edge := range c.path {
 			// Find the caller that used the function parameter's parent (the function).
 			if edge.Callee.Func == v.Parent() {
+				// Only step back to each caller once for a given parameter,
+				// which ensures recursive call chains terminate, even when
+				// checking values with a different set of visited values.
+				step := callerValue{caller: edge.Caller, value: value}
+				if _, ok := c.steps[step]; ok {
+					continue
+				}
+				c.steps[step] = struct{}{}
+
 				// Inspect the instructions of the caller's function to identify
 				// the relevant call using the function parameter.
 				for _, block := range edge.Caller.Func.DomPreorder() {
@@ -214,8 +1237,8 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 						if !ok {
 							continue
 						}
-						if callInstr.Call.Value.Pos() == edge.Callee.Func.Pos() {
-							tainted, src, tv := checkSSAInstruction(path, sources, instr, visited)
+						if edge.Site == ssa.CallInstruction(callInstr) || callInstr.Call.Value.Pos() == edge.Callee.Func.Pos() {
+							tainted, src, tv := c.checkSSAInstruction(instr, visited)
 							if tainted {
 								return true, src, tv
 							}
@@ -229,38 +1252,61 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 	// 2. See if any of the arguments was a source.
 	// 3. See if the call value calls a source (anonymous functions).
 	case *ssa.Call:
-		// 1. Handle the case where we finally called a source.
-		callTypeStr := value.Call.Value.String()
-		if src, ok := sources.includes(callTypeStr); ok {
-			return true, src, value.Call.Value
+		// 1. Handle the case where we finally called a source, using
+		//    the call itself as the source value (not the function),
+		//    so the source can be located within the program.
+		if src, ok := c.sourceCall(value.Common()); ok {
+			return true, src, value
 		}
 		// 2. Handle the arguments of the call.
 		for _, arg := range value.Call.Args {
-			tainted, src, tv := checkSSAValue(path, sources, arg, visited)
+			tainted, src, tv := c.checkSSAValue(arg, visited)
 			if tainted {
 				return true, src, tv
 			}
 		}
 		// 3. Handle the case of a *ssa.Call from an anonymous function (*ssa.MakeClosure).
-		tainted, src, tv := checkSSAValue(path, sources, value.Call.Value, visited)
+		tainted, src, tv := c.checkSSAValue(value.Call.Value, visited)
 		if tainted {
 			return true, src, tv
 		}
 	// Memory allocations or addressing can be traversed using the value's
 	// referrers. Each referrer is either an SSA value or instruction.
 	case *ssa.Alloc:
+		// Check if the memory was decoded from a tainted value, such as
+		// json.NewDecoder(r.Body).Decode(&v), which taints all of it.
+		tainted, src, tv := c.checkDecodeTarget(value, visited)
+		if tainted {
+			return true, src, tv
+		}
+
+		// Check if the memory was filled by calling a source, such as
+		// rows.Scan(&name), if it is one of the sources.
+		tainted, src, tv = c.checkSourceTarget(value)
+		if tainted {
+			return true, src, tv
+		}
+
+		// Check if the memory was set using reflection, if enabled.
+		if c.opts.Reflect {
+			tainted, src, tv := c.checkReflectTarget(value, visited)
+			if tainted {
+				return true, src, tv
+			}
+		}
+
 		refs := value.Referrers()
 		for _, ref := range *refs {
 			refVal, isVal := ref.(ssa.Value)
 			if isVal {
-				tainted, src, tv := checkSSAValue(path, sources, refVal, visited)
+				tainted, src, tv := c.checkSSAValue(refVal, visited)
 				if tainted {
 					return true, src, tv
 				}
 				continue
 			}
 
-			tainted, src, tv := checkSSAInstruction(path, sources, ref, visited)
+			tainted, src, tv := c.checkSSAInstruction(ref, visited)
 			if tainted {
 				return true, src, tv
 			}
@@ -276,14 +1322,14 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 		for _, ref := range *refs {
 			refVal, isVal := ref.(ssa.Value)
 			if isVal {
-				tainted, src, tv := checkSSAValue(path, sources, refVal, visited)
+				tainted, src, tv := c.checkSSAValue(refVal, visited)
 				if tainted {
 					return true, src, tv
 				}
 				continue
 			}
 
-			tainted, src, tv := checkSSAInstruction(path, sources, ref, visited)
+			tainted, src, tv := c.checkSSAInstruction(ref, visited)
 			if tainted {
 				return true, src, tv
 			}
@@ -305,6 +1351,11 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 		//
 		// TODO: consider checking parentFn params and other places?
 		parentFn := value.Parent().Parent()
+		if parentFn == nil {
+			// Synthetic functions, such as bound method closures, have
+			// no parent function to check.
+			break
+		}
 		for _, block := range parentFn.DomPreorder() {
 			for _, instr := range block.Instrs {
 				// fmt.Printf("\t - check SSA value %s: %[1]T ~ %[2]v\n", instr, value.Name())
@@ -315,7 +1366,7 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 				alloc, isalloc := val.(*ssa.Alloc)
 				if isalloc {
 					if alloc.Comment == value.Name() {
-						tainted, src, tv := checkSSAValue(path, sources, val, visited)
+						tainted, src, tv := c.checkSSAValue(val, visited)
 						if tainted {
 							return true, src, tv
 						}
@@ -340,7 +1391,7 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 				// 		}()
 				//  })
 				//
-				tainted, src, tv := checkSSAValue(path, sources, val, valueSet{})
+				tainted, src, tv := c.checkSSAValue(val, valueSet{})
 				if tainted {
 					return true, src, tv
 				}
@@ -351,19 +1402,19 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 		for _, ref := range *refs {
 			refVal, isVal := ref.(ssa.Value)
 			if isVal {
-				tainted, src, tv := checkSSAValue(path, sources, refVal, visited)
+				tainted, src, tv := c.checkSSAValue(refVal, visited)
 				if tainted {
 					return true, src, tv
 				}
 				continue
 			}
 
-			tainted, src, tv := checkSSAInstruction(path, sources, ref, visited)
+			tainted, src, tv := c.checkSSAInstruction(ref, visited)
 			if tainted {
 				return true, src, tv
 			}
 		}
-		tainted, src, tv := checkSSAValue(path, sources, value.X, visited)
+		tainted, src, tv := c.checkSSAValue(value.X, visited)
 		if tainted {
 			return true, src, tv
 		}
@@ -376,11 +1427,16 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 			value.X.Type().String()
 			=? "*net/http.Request"
 		*/
-		if src, ok := sources.includes(value.X.Type().String()); ok {
-			return true, src, value
+		// Values allocated within the function, such as a response message,
+		// aren't sources because of their type, only the values stored in
+		// them, which are checked using the allocation's referrers.
+		if _, local := value.X.(*ssa.Alloc); !local {
+			if src, ok := c.sourceType(value.X.Type()); ok {
+				return true, src, value
+			}
 		}
 
-		tainted, src, tv := checkSSAValue(path, sources, value.X, visited)
+		tainted, src, tv := c.checkSSAValue(value.X, visited)
 		if tainted {
 			return true, src, tv
 		}
@@ -389,14 +1445,14 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 		for _, ref := range *refs {
 			refVal, isVal := ref.(ssa.Value)
 			if isVal {
-				tainted, src, tv := checkSSAValue(path, sources, refVal, visited)
+				tainted, src, tv := c.checkSSAValue(refVal, visited)
 				if tainted {
 					return true, src, tv
 				}
 				continue
 			}
 
-			tainted, src, tv := checkSSAInstruction(path, sources, ref, visited)
+			tainted, src, tv := c.checkSSAInstruction(ref, visited)
 			if tainted {
 				return true, src, tv
 			}
@@ -405,60 +1461,69 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 		for _, ref := range *indexableValueRefs {
 			refVal, isVal := ref.(ssa.Value)
 			if isVal {
-				tainted, src, tv := checkSSAValue(path, sources, refVal, visited)
+				tainted, src, tv := c.checkSSAValue(refVal, visited)
 				if tainted {
 					return true, src, tv
 				}
 				continue
 			}
 
-			tainted, src, tv := checkSSAInstruction(path, sources, ref, visited)
+			tainted, src, tv := c.checkSSAInstruction(ref, visited)
 			if tainted {
 				return true, src, tv
 			}
 		}
 	case *ssa.MakeClosure:
-		tainted, src, tv := checkSSAValue(path, sources, value.Fn, visited)
+		tainted, src, tv := c.checkSSAValue(value.Fn, visited)
 		if tainted {
 			return true, src, tv
 		}
 		for _, binding := range value.Bindings {
-			tainted, src, tv := checkSSAValue(path, sources, binding, visited)
+			tainted, src, tv := c.checkSSAValue(binding, visited)
 			if tainted {
 				return true, src, tv
 			}
 		}
 	case *ssa.BinOp:
 		// Check the left hand side operands of the binary operations.
-		tainted, src, tv := checkSSAValue(path, sources, value.X, visited) // left
+		tainted, src, tv := c.checkSSAValue(value.X, visited) // left
 		if tainted {
 			return true, src, tv
 		}
-		tainted, src, tv = checkSSAValue(path, sources, value.Y, visited) // right
+		tainted, src, tv = c.checkSSAValue(value.Y, visited) // right
 		if tainted {
 			return true, src, tv
 		}
+	case *ssa.Phi:
+		// Check each of the values merged by the phi node, such as
+		// the initial and loop-carried values of an accumulator.
+		for _, edge := range value.Edges {
+			tainted, src, tv := c.checkSSAValue(edge, visited)
+			if tainted {
+				return true, src, tv
+			}
+		}
 	case *ssa.UnOp:
 		// Check the single operand.
-		tainted, src, tv := checkSSAValue(path, sources, value.X, visited)
+		tainted, src, tv := c.checkSSAValue(value.X, visited)
 		if tainted {
 			return true, src, tv
 		}
 	case *ssa.Slice:
 		// Check the sliced value.
-		tainted, src, tv := checkSSAValue(path, sources, value.X, visited)
+		tainted, src, tv := c.checkSSAValue(value.X, visited)
 		if tainted {
 			return true, src, tv
 		}
 	case *ssa.MakeInterface:
 		// Check the value being made into an interface.
-		tainted, src, tv := checkSSAValue(path, sources, value.X, visited)
+		tainted, src, tv := c.checkSSAValue(value.X, visited)
 		if tainted {
 			return true, src, tv
 		}
 	case *ssa.ChangeInterface:
 		// Check the value being changed into an interface.
-		tainted, src, tv := checkSSAValue(path, sources, value.X, visited)
+		tainted, src, tv := c.checkSSAValue(value.X, visited)
 		if tainted {
 			return true, src, tv
 		}
@@ -468,39 +1533,60 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 		for _, ref := range *refs {
 			refVal, isVal := ref.(ssa.Value)
 			if isVal {
-				tainted, src, tv := checkSSAValue(path, sources, refVal, visited)
+				tainted, src, tv := c.checkSSAValue(refVal, visited)
 				if tainted {
 					return true, src, tv
 				}
 				continue
 			}
 
-			tainted, src, tv := checkSSAInstruction(path, sources, ref, visited)
+			tainted, src, tv := c.checkSSAInstruction(ref, visited)
 			if tainted {
 				return true, src, tv
 			}
 		}
 	case *ssa.TypeAssert:
 		// Check the value being type asserted.
-		tainted, src, tv := checkSSAValue(path, sources, value.X, visited)
+		tainted, src, tv := c.checkSSAValue(value.X, visited)
 		if tainted {
 			return true, src, tv
 		}
 	case *ssa.Convert:
 		// Check the value being converted.
-		tainted, src, tv := checkSSAValue(path, sources, value.X, visited)
+		tainted, src, tv := c.checkSSAValue(value.X, visited)
+		if tainted {
+			return true, src, tv
+		}
+	case *ssa.ChangeType:
+		// Check the value being changed into another type with the
+		// same underlying type, such as string to template.HTML.
+		tainted, src, tv := c.checkSSAValue(value.X, visited)
 		if tainted {
 			return true, src, tv
 		}
 	case *ssa.Extract:
+		// Check only the specific result being extracted from calls to
+		// functions we can see the body of, so other (clean) results
+		// aren't tainted too, e.g. a in a, b := f(r).
+		if call, ok := value.Tuple.(*ssa.Call); ok {
+			// Every result of a call to a source is tainted, even if we
+			// can see the body of the source.
+			if src, ok := c.sourceCall(call.Common()); ok {
+				return true, src, call
+			}
+			if fn := call.Call.StaticCallee(); fn != nil && len(fn.Blocks) > 0 {
+				return c.checkResult(call, fn, value.Index, visited)
+			}
+		}
+
 		// Check the value being extracted.
-		tainted, src, tv := checkSSAValue(path, sources, value.Tuple, visited)
+		tainted, src, tv := c.checkSSAValue(value.Tuple, visited)
 		if tainted {
 			return true, src, tv
 		}
 	case *ssa.Lookup:
 		// Check the string or map value being looked up.
-		tainted, src, tv := checkSSAValue(path, sources, value.X, visited)
+		tainted, src, tv := c.checkSSAValue(value.X, visited)
 		if tainted {
 			return true, src, tv
 		}
@@ -511,14 +1597,14 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 			for _, ref := range *refs {
 				refVal, isVal := ref.(ssa.Value)
 				if isVal {
-					tainted, src, tv := checkSSAValue(path, sources, refVal, visited)
+					tainted, src, tv := c.checkSSAValue(refVal, visited)
 					if tainted {
 						return true, src, tv
 					}
 					continue
 				}
 
-				tainted, src, tv := checkSSAInstruction(path, sources, ref, visited)
+				tainted, src, tv := c.checkSSAInstruction(ref, visited)
 				if tainted {
 					return true, src, tv
 				}
@@ -530,14 +1616,14 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 			for _, ref := range *refs {
 				refVal, isVal := ref.(ssa.Value)
 				if isVal {
-					tainted, src, tv := checkSSAValue(path, sources, refVal, visited)
+					tainted, src, tv := c.checkSSAValue(refVal, visited)
 					if tainted {
 						return true, src, tv
 					}
 					continue
 				}
 
-				tainted, src, tv := checkSSAInstruction(path, sources, ref, visited)
+				tainted, src, tv := c.checkSSAInstruction(ref, visited)
 				if tainted {
 					return true, src, tv
 				}
@@ -550,20 +1636,54 @@ func checkSSAValue(path callgraphutil.Path, sources Sources, v ssa.Value, visite
 	return false, "", nil
 }
 
+// checkResult checks the result at the given index returned by the called
+// function, using the call's arguments for the function's parameters.
+func (c *checker) checkResult(call *ssa.Call, fn *ssa.Function, index int, visited valueSet) (bool, string, ssa.Value) {
+	if len(fn.Params) == len(call.Call.Args) {
+		if c.args == nil {
+			c.args = map[*ssa.Parameter]ssa.Value{}
+		}
+		for i, param := range fn.Params {
+			prev, ok := c.args[param]
+			c.args[param] = call.Call.Args[i]
+			defer func(param *ssa.Parameter) {
+				if ok {
+					c.args[param] = prev
+				} else {
+					delete(c.args, param)
+				}
+			}(param)
+		}
+	}
+
+	for _, block := range fn.Blocks {
+		ret, ok := block.Instrs[len(block.Instrs)-1].(*ssa.Return)
+		if !ok || index >= len(ret.Results) {
+			continue
+		}
+		tainted, src, tv := c.checkSSAValue(ret.Results[index], visited)
+		if tainted {
+			return true, src, tv
+		}
+	}
+
+	return false, "", nil
+}
+
 // checkSSAInstruction is used internally by checkSSAValue when it needs to traverse
 // SSA instructions, like the contents of a calling function.
-func checkSSAInstruction(path callgraphutil.Path, sources Sources, i ssa.Instruction, visited valueSet) (bool, string, ssa.Value) {
+func (c *checker) checkSSAInstruction(i ssa.Instruction, visited valueSet) (bool, string, ssa.Value) {
 	// fmt.Printf("! check SSA instr %s: %[1]T\n", i)
 
 	switch instr := i.(type) {
 	case *ssa.Store:
 		// Store instructions need to be checked for both the value being stored,
 		// and the address being stored to.
-		tainted, src, tv := checkSSAValue(path, sources, instr.Val, visited)
+		tainted, src, tv := c.checkSSAValue(instr.Val, visited)
 		if tainted {
 			return true, src, tv
 		}
-		tainted, src, tv = checkSSAValue(path, sources, instr.Addr, visited)
+		tainted, src, tv = c.checkSSAValue(instr.Addr, visited)
 		if tainted {
 			return true, src, tv
 		}
@@ -574,7 +1694,7 @@ func checkSSAInstruction(path callgraphutil.Path, sources Sources, i ssa.Instruc
 				continue
 			}
 			iv := *instrValue
-			tainted, src, tv := checkSSAValue(path, sources, iv, visited)
+			tainted, src, tv := c.checkSSAValue(iv, visited)
 			if tainted {
 				return true, src, tv
 			}
@@ -582,12 +1702,12 @@ func checkSSAInstruction(path callgraphutil.Path, sources Sources, i ssa.Instruc
 	case *ssa.MapUpdate:
 		// Map update instructions need to be checked for both the map being updated,
 		// and the key and value being updated.
-		tainted, src, tv := checkSSAValue(path, sources, instr.Key, visited)
+		tainted, src, tv := c.checkSSAValue(instr.Key, visited)
 		if tainted {
 			return true, src, tv
 		}
 
-		tainted, src, tv = checkSSAValue(path, sources, instr.Value, visited)
+		tainted, src, tv = c.checkSSAValue(instr.Value, visited)
 		if tainted {
 			return true, src, tv
 		}
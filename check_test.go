@@ -0,0 +1,464 @@
+package taint_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go/token"
+	"go/types"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/callgraphutil"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/ssa"
+)
+
+func TestCheckFunc(t *testing.T) {
+	mainFn, _ := loadSSA(t, "sqli")
+
+	search := mainFn.Pkg.Func("search")
+	if search == nil {
+		t.Fatal("search function not found")
+	}
+
+	results := taint.CheckFunc(
+		search,
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+	)
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %v", len(results), results)
+	}
+
+	if sink := results[0].Path.Last().Callee.Func.String(); sink != "(*database/sql.DB).Query" {
+		t.Errorf("expected sink %q, got %q", "(*database/sql.DB).Query", sink)
+	}
+}
+
+func TestCheckWithOptionsOnEdge(t *testing.T) {
+	cg := loadCallGraph(t, "sqli")
+
+	var found bool
+
+	taint.CheckWithOptions(
+		cg,
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+		taint.Options{
+			OnEdge: func(from, to ssa.Value) {
+				fromCall, ok := from.(*ssa.Call)
+				if !ok || fromCall.Call.Value.String() != "fmt.Sprintf" {
+					return
+				}
+				toCall, ok := to.(*ssa.Call)
+				if !ok || toCall.Call.Value.String() != "(*database/sql.DB).Query" {
+					return
+				}
+				found = true
+			},
+		},
+	)
+
+	if !found {
+		t.Fatal("expected OnEdge to be called for the fmt.Sprintf → (*database/sql.DB).Query edge")
+	}
+}
+
+func TestCheckRecursion(t *testing.T) {
+	cg := loadCallGraph(t, "recursion")
+
+	results := taint.Check(
+		cg,
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+	)
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %v", len(results), results)
+	}
+}
+
+func TestCheckFanOut(t *testing.T) {
+	cg := loadCallGraph(t, "fanout")
+
+	results := taint.Check(
+		cg,
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+	)
+
+	// Each sink reached from the same source is a distinct result.
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %v", len(results), results)
+	}
+
+	if results[0].Position() == results[1].Position() {
+		t.Errorf("expected results for different sinks, got %v for both", results[0].Position())
+	}
+
+	if results[0].SourceValue != results[1].SourceValue {
+		t.Errorf("expected results to share a source, got %v and %v", results[0].SourceValue, results[1].SourceValue)
+	}
+}
+
+func TestCheckNames(t *testing.T) {
+	cg := loadCallGraph(t, "sqli")
+
+	results := taint.Check(
+		cg,
+		taint.NewSources("*net/http.Request", "os.Getenv"),
+		taint.NewSinks("(*database/sql.DB).Query", "(*database/sql.DB).Exec"),
+	)
+
+	if len(results) == 0 {
+		t.Fatal("expected results")
+	}
+
+	for _, result := range results {
+		if result.SourceName != "*net/http.Request" {
+			t.Errorf("expected source name %q, got %q", "*net/http.Request", result.SourceName)
+		}
+		if result.SinkName != "(*database/sql.DB).Query" {
+			t.Errorf("expected sink name %q, got %q", "(*database/sql.DB).Query", result.SinkName)
+		}
+	}
+}
+
+func TestSinkByInterfaceMethod(t *testing.T) {
+	cg := loadCallGraph(t, "iface")
+
+	sqlPkg := cg.Root.Func.Prog.ImportedPackage("database/sql")
+	if sqlPkg == nil {
+		t.Fatal("database/sql package not found")
+	}
+
+	rows := types.NewPointer(sqlPkg.Pkg.Scope().Lookup("Rows").Type())
+
+	// interface { Query(query string, args ...any) (*sql.Rows, error) }
+	query := types.NewFunc(token.NoPos, nil, "Query", types.NewSignatureType(nil, nil, nil,
+		types.NewTuple(
+			types.NewVar(token.NoPos, nil, "query", types.Typ[types.String]),
+			types.NewVar(token.NoPos, nil, "args", types.NewSlice(types.Universe.Lookup("any").Type())),
+		),
+		types.NewTuple(
+			types.NewVar(token.NoPos, nil, "", rows),
+			types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type()),
+		),
+		true,
+	))
+	iface := types.NewInterfaceType([]*types.Func{query}, nil).Complete()
+
+	sinks := taint.SinkByInterfaceMethod(cg, iface, "Query")
+
+	for _, sink := range []string{"(*database/sql.DB).Query", "(*github.com/picatz/taint/testdata/iface.store).Query"} {
+		if _, ok := sinks[sink]; !ok {
+			t.Errorf("expected sink %q, got %v", sink, sinks)
+		}
+	}
+
+	if len(sinks) != 2 {
+		t.Fatalf("expected 2 sinks, got %v", sinks)
+	}
+
+	results := taint.Check(cg, taint.NewSources("*net/http.Request"), sinks)
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %v", len(results), results)
+	}
+}
+
+func TestCheckExtract(t *testing.T) {
+	cg := loadCallGraph(t, "extract")
+
+	results := taint.Check(
+		cg,
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+	)
+
+	// Only the handlers using the tainted value returned by split
+	// should be reported, not the clean query returned alongside it.
+	reported := map[string]bool{}
+	for _, result := range results {
+		reported[result.SinkValue.Parent().Name()] = true
+	}
+
+	if len(results) != 2 || !reported["tainted"] || !reported["swapped"] {
+		t.Fatalf("expected results for tainted and swapped, got %d: %v", len(results), results)
+	}
+}
+
+func TestCheckExtractSource(t *testing.T) {
+	cg := loadCallGraph(t, "extract")
+
+	// Every result of a source is tainted, even though its body is
+	// visible, and returns a clean query.
+	results := taint.Check(
+		cg,
+		taint.NewSources("github.com/picatz/taint/testdata/extract.split"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+	)
+
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d: %v", len(results), results)
+	}
+}
+
+func TestCheckScope(t *testing.T) {
+	cg := loadCallGraph(t, "scope")
+
+	scope := map[*ssa.Function]bool{}
+	for _, n := range callgraphutil.Reachable(cg, "github.com/picatz/taint/testdata/scope.search") {
+		scope[n.Func] = true
+	}
+
+	// The name given by the handler is found first, but it's outside
+	// of the scope, so the request given to search is found instead.
+	results := taint.CheckWithOptions(
+		cg,
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+		taint.Options{Scope: scope},
+	)
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %v", len(results), results)
+	}
+
+	if fn := results[0].SourceValue.Parent().Name(); fn != "search" {
+		t.Errorf("expected a source within search, got %q", fn)
+	}
+}
+
+func TestCheckContext(t *testing.T) {
+	cg := loadCallGraph(t, "sqli")
+
+	results, err := taint.CheckContext(
+		context.Background(),
+		cg,
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both search and lookup pass user input to the query.
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %v", len(results), results)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err = taint.CheckContext(
+		ctx,
+		cg,
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if len(results) != 0 {
+		t.Fatalf("expected no results, got %d: %v", len(results), results)
+	}
+}
+
+func TestCheckContextPanic(t *testing.T) {
+	cg := loadCallGraph(t, "sqli")
+
+	// Add a malformed edge without a call site to the sink, which panics
+	// when the path is checked, but must not stop the other paths.
+	var query *callgraph.Node
+	for fn, node := range cg.Nodes {
+		if fn.String() == "(*database/sql.DB).Query" {
+			query = node
+		}
+	}
+	if query == nil {
+		t.Fatal("query node not found")
+	}
+	callgraph.AddEdge(cg.Root, nil, query)
+
+	results, err := taint.CheckContext(
+		context.Background(),
+		cg,
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+	)
+
+	var panicErr *callgraphutil.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected a panic error, got %v", err)
+	}
+
+	if panicErr.Func != cg.Root.Func {
+		t.Errorf("expected panic for %v, got %v", cg.Root.Func, panicErr.Func)
+	}
+
+	// Both search and lookup pass user input to the query.
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %v", len(results), results)
+	}
+}
+
+func TestSortResults(t *testing.T) {
+	sinks := taint.NewSinks("(*database/sql.DB).Query")
+	sources := taint.NewSources("*net/http.Request")
+
+	positions := func() []token.Position {
+		results := taint.Check(loadCallGraph(t, "sqli"), sources, sinks)
+		if len(results) < 2 {
+			t.Fatalf("expected multiple results, got %d", len(results))
+		}
+
+		var positions []token.Position
+		for _, result := range results {
+			positions = append(positions, result.Position(), result.SourcePosition())
+		}
+		return positions
+	}
+
+	first, second := positions(), positions()
+
+	for i := 2; i < len(first); i += 2 {
+		prev, pos := first[i-2], first[i]
+		if pos.Filename < prev.Filename || (pos.Filename == prev.Filename && pos.Offset < prev.Offset) {
+			t.Errorf("expected results sorted by sink position, got %v before %v", prev, pos)
+		}
+	}
+
+	if len(first) != len(second) {
+		t.Fatalf("expected %d positions, got %d", len(first), len(second))
+	}
+
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("expected identical ordering across runs, got %v and %v at %d", first[i], second[i], i)
+		}
+	}
+}
+
+func TestCheckDebugOutput(t *testing.T) {
+	cg := loadCallGraph(t, "sqli")
+
+	var buf bytes.Buffer
+	defer func(w io.Writer) { taint.DebugOutput = w }(taint.DebugOutput)
+	taint.DebugOutput = &buf
+
+	check := func() {
+		taint.Check(
+			cg,
+			taint.NewSources("*net/http.Request"),
+			taint.NewSinks("(*database/sql.DB).Query"),
+		)
+	}
+
+	// Without TAINT_DEBUG set, nothing is written.
+	t.Setenv("TAINT_DEBUG", "")
+	check()
+	if buf.Len() != 0 {
+		t.Fatalf("expected no debug output, got:\n%s", buf.String())
+	}
+
+	t.Setenv("TAINT_DEBUG", "1")
+	check()
+
+	out := buf.String()
+	for _, want := range []string{
+		"taint: source *net/http.Request\n",
+		"taint: sink (*database/sql.DB).Query called at ",
+		"taint: tainted by *net/http.Request\n",
+		" → ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected debug output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestCheckWithOptionsReflect(t *testing.T) {
+	cg := loadCallGraph(t, "reflectbind")
+
+	sources := taint.NewSources("*net/http.Request")
+	sinks := taint.NewSinks("(*database/sql.DB).Query")
+
+	// Values set using reflection aren't tracked by default.
+	if results := taint.Check(cg, sources, sinks); len(results) != 0 {
+		t.Fatalf("expected no results without the reflect option, got %d: %v", len(results), results)
+	}
+
+	results := taint.CheckWithOptions(cg, sources, sinks, taint.Options{Reflect: true})
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %v", len(results), results)
+	}
+
+	if src := results[0].SourceType; src != "*net/http.Request" {
+		t.Errorf("expected source %q, got %q", "*net/http.Request", src)
+	}
+}
+
+func TestCheckEntryFunc(t *testing.T) {
+	cg := loadCallGraph(t, "fanin")
+
+	results := taint.Check(
+		cg,
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+	)
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %v", len(results), results)
+	}
+
+	entries := map[string]bool{}
+	for _, result := range results {
+		entries[result.EntryFunc] = true
+	}
+
+	for _, entry := range []string{
+		"github.com/picatz/taint/testdata/fanin.userHandler",
+		"github.com/picatz/taint/testdata/fanin.adminHandler",
+	} {
+		if !entries[entry] {
+			t.Errorf("expected a result with entry function %q, got %v", entry, entries)
+		}
+	}
+}
+
+func TestTainted(t *testing.T) {
+	cg := loadCallGraph(t, "sqli")
+
+	sources := taint.NewSources("*net/http.Request")
+
+	var search *taint.Result
+	for _, result := range taint.Check(cg, sources, taint.NewSinks("(*database/sql.DB).Query")) {
+		if result.EntryFunc == "github.com/picatz/taint/testdata/sqli.search" {
+			result := result
+			search = &result
+		}
+	}
+	if search == nil {
+		t.Fatal("expected a result for the search function")
+	}
+
+	site := search.Path.Last().Site
+	query, ok := site.Common().Args[1].(*ssa.Call)
+	if !ok || query.Call.Value.String() != "fmt.Sprintf" {
+		t.Fatalf("expected the query to be built using fmt.Sprintf, got %v", site.Common().Args[1])
+	}
+
+	if !taint.Tainted(search.Path, query, sources) {
+		t.Errorf("expected the query to be tainted")
+	}
+
+	if format := query.Call.Args[0]; taint.Tainted(search.Path, format, sources) {
+		t.Errorf("expected the format string %v not to be tainted", format)
+	}
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/picatz/taint/grpc/reflected"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(reflected.Analyzer)
+}
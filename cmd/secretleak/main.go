@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/picatz/taint/secrets/leak"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(leak.Analyzer)
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/picatz/taint"
+	"golang.org/x/term"
+)
+
+// browserKey is a key press handled by the result browser.
+type browserKey int
+
+const (
+	keyUp browserKey = iota
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyQuit
+)
+
+// browserKeys maps the input sequences sent by a terminal in raw mode to
+// the keys handled by the result browser, including vi-style keys.
+var browserKeys = []struct {
+	seq string
+	key browserKey
+}{
+	{"\x1b[A", keyUp},
+	{"\x1b[B", keyDown},
+	{"\x1b[5~", keyPageUp},
+	{"\x1b[6~", keyPageDown},
+	{"\x1b[H", keyHome},
+	{"\x1b[F", keyEnd},
+	{"k", keyUp},
+	{"j", keyDown},
+	{"g", keyHome},
+	{"G", keyEnd},
+	{"q", keyQuit},
+	{"\x03", keyQuit}, // Ctrl-C
+	{"\x1b", keyQuit}, // Escape, matched after the sequences it prefixes.
+}
+
+// parseKeys returns the keys in the given terminal input, ignoring any
+// input which isn't handled by the result browser.
+func parseKeys(input []byte) []browserKey {
+	var keys []browserKey
+
+	for s := string(input); s != ""; {
+		matched := false
+		for _, k := range browserKeys {
+			if strings.HasPrefix(s, k.seq) {
+				keys = append(keys, k.key)
+				s = s[len(k.seq):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			s = s[1:]
+		}
+	}
+
+	return keys
+}
+
+// browser is a full-screen view of check results, listing the results on
+// the left, and the selected result's path and source on the right.
+//
+// It's updated with the keys pressed (see update), then rendered to the
+// terminal (see view), so it can be tested without a terminal.
+type browser struct {
+	results taint.Results
+
+	// selected is the index of the selected result.
+	selected int
+	// offset is the index of the first result listed, which is scrolled
+	// to keep the selected result visible.
+	offset int
+
+	width, height int
+
+	// quit is true once the browser should be closed.
+	quit bool
+}
+
+// newBrowser returns a browser of the given results, sized to the given
+// terminal width and height.
+func newBrowser(results taint.Results, width, height int) *browser {
+	return &browser{
+		results: results,
+		width:   width,
+		height:  height,
+	}
+}
+
+// listHeight returns the number of results which can be listed at once,
+// leaving room for the header and footer.
+func (b *browser) listHeight() int {
+	return max(b.height-2, 1)
+}
+
+// update updates the browser for the given key press.
+func (b *browser) update(key browserKey) {
+	switch key {
+	case keyUp:
+		b.selected--
+	case keyDown:
+		b.selected++
+	case keyPageUp:
+		b.selected -= b.listHeight()
+	case keyPageDown:
+		b.selected += b.listHeight()
+	case keyHome:
+		b.selected = 0
+	case keyEnd:
+		b.selected = len(b.results) - 1
+	case keyQuit:
+		b.quit = true
+	}
+
+	b.selected = min(max(b.selected, 0), max(len(b.results)-1, 0))
+
+	// Scroll the list to keep the selected result visible.
+	if b.selected < b.offset {
+		b.offset = b.selected
+	}
+	if b.selected >= b.offset+b.listHeight() {
+		b.offset = b.selected - b.listHeight() + 1
+	}
+}
+
+// view renders the browser, filling its width and height.
+func (b *browser) view() string {
+	if len(b.results) == 0 {
+		return "no results to browse\n"
+	}
+
+	listWidth := b.width * 2 / 5
+	detailWidth := b.width - listWidth - 1
+
+	var list strings.Builder
+	for i := b.offset; i < len(b.results) && i < b.offset+b.listHeight(); i++ {
+		line := resultLabel(b.results[i])
+		if i == b.selected {
+			list.WriteString(styleBold.Render("> "+line) + "\n")
+			continue
+		}
+		list.WriteString(styleFaint.Render("  "+line) + "\n")
+	}
+
+	pane := func(width int) lipgloss.Style {
+		return lipgloss.NewStyle().Width(width).MaxWidth(width).Height(b.listHeight()).MaxHeight(b.listHeight())
+	}
+
+	body := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		pane(listWidth).Render(list.String()),
+		" ",
+		pane(detailWidth).Render(resultDetail(b.results[b.selected])),
+	)
+
+	header := styleBold.Render("results") + " " + styleFaint.Render(fmt.Sprintf("(%d/%d)", b.selected+1, len(b.results)))
+	footer := styleFaint.Render("↑/↓ select • pgup/pgdn page • home/end first/last • q quit")
+
+	return header + "\n" + body + "\n" + footer
+}
+
+// resultLabel returns the line listing the given result in the browser,
+// such as "main.go:42 (*database/sql.DB).Query".
+func resultLabel(result taint.Result) string {
+	label := result.SinkName
+	if pos := result.Position(); pos.IsValid() {
+		label = fmt.Sprintf("%s:%d %s", filepath.Base(pos.Filename), pos.Line, label)
+	}
+	if result.Rule != "" {
+		label = result.Rule + " " + label
+	}
+	return label
+}
+
+// resultDetail returns the details of the given result shown when it's
+// selected in the browser: its source and sink, each hop of its path, and
+// the source code where the source was introduced.
+func resultDetail(result taint.Result) string {
+	var b strings.Builder
+
+	b.WriteString(styleBold.Render("source: ") + result.SourceName)
+	if pos := result.SourcePosition(); pos.IsValid() {
+		b.WriteString(" " + styleFaint.Render("("+pos.String()+")"))
+	}
+	b.WriteString("\n")
+
+	b.WriteString(styleBold.Render("sink: ") + result.SinkName)
+	if pos := result.Position(); pos.IsValid() {
+		b.WriteString(" " + styleFaint.Render("("+pos.String()+")"))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(verbosePath(result.Path))
+
+	if snippet := sourceSnippet(result.SourcePosition(), 2); snippet != "" {
+		b.WriteString("\n" + snippet)
+	}
+
+	return b.String()
+}
+
+// sourceSnippet returns the lines of source code around the given position,
+// numbered, with the position's line marked, or an empty string if the
+// source can't be read.
+func sourceSnippet(pos token.Position, context int) string {
+	if !pos.IsValid() {
+		return ""
+	}
+
+	src, err := os.ReadFile(pos.Filename)
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(string(src), "\n")
+
+	var b strings.Builder
+	for i := max(pos.Line-context, 1); i <= min(pos.Line+context, len(lines)); i++ {
+		line := fmt.Sprintf("%4d  %s", i, strings.ReplaceAll(lines[i-1], "\t", "    "))
+		if i == pos.Line {
+			b.WriteString(styleBold.Render(">"+line) + "\n")
+			continue
+		}
+		b.WriteString(styleFaint.Render(" "+line) + "\n")
+	}
+	return b.String()
+}
+
+// runBrowser renders the given browser to w on the terminal's alternate
+// screen, updating it with the keys read from r until it's quit.
+func runBrowser(ctx context.Context, r io.Reader, w *bufio.Writer, b *browser) error {
+	// Use the alternate screen, hiding the cursor, restoring both once done.
+	w.WriteString("\033[?1049h\033[?25l")
+	defer func() {
+		w.WriteString("\033[?25h\033[?1049l")
+		w.Flush()
+	}()
+
+	buf := make([]byte, 64)
+
+	for !b.quit && ctx.Err() == nil {
+		w.WriteString("\033[H\033[2J" + b.view())
+		w.Flush()
+
+		n, err := r.Read(buf)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range parseKeys(buf[:n]) {
+			b.update(key)
+		}
+	}
+
+	return nil
+}
+
+var builtinCommandBrowse = &command{
+	name: "browse",
+	desc: "browse the results of the last check in a full-screen view",
+	examples: []string{
+		"browse",
+	},
+	fn: func(ctx context.Context, bt *bufio.Writer, args []string, flags map[string]string) error {
+		if len(lastResults) == 0 {
+			bt.WriteString("no results to browse, run check first\n")
+			bt.Flush()
+			return nil
+		}
+
+		if !term.IsTerminal(0) {
+			bt.WriteString("browse requires an interactive terminal\n")
+			bt.Flush()
+			return nil
+		}
+
+		width, height, err := term.GetSize(0)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return runBrowser(ctx, os.Stdin, bt, newBrowser(lastResults, width, height))
+	},
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	keys := parseKeys([]byte("\x1b[B\x1b[Bjx\x1b[A\x1b[6~G\x1b[Hq\x1b"))
+
+	want := []browserKey{keyDown, keyDown, keyDown, keyUp, keyPageDown, keyEnd, keyHome, keyQuit, keyQuit}
+	if len(keys) != len(want) {
+		t.Fatalf("expected %d keys, got %d: %v", len(want), len(keys), keys)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("expected key %d to be %v, got %v", i, want[i], keys[i])
+		}
+	}
+}
+
+func TestBrowser(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load ../../testdata/fanin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = builtinCommands.eval(context.Background(), bt, "check *net/http.Request (*database/sql.DB).Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lastResults) != 2 {
+		t.Fatalf("expected the check's 2 results to browse, got %d", len(lastResults))
+	}
+
+	// A single result is listed at a time, so the list scrolls.
+	b := newBrowser(lastResults, 120, 3)
+
+	if view := b.view(); !strings.Contains(view, "(1/2)") || !strings.Contains(view, lastResults[0].SourcePosition().String()) {
+		t.Fatalf("expected the first result to be selected, got:\n%s", view)
+	}
+
+	b.update(keyDown)
+	if b.selected != 1 || b.offset != 1 {
+		t.Fatalf("expected the second result to be selected and visible, got selected %d, offset %d", b.selected, b.offset)
+	}
+
+	if view := b.view(); !strings.Contains(view, "(2/2)") || !strings.Contains(view, lastResults[1].SourcePosition().String()) {
+		t.Fatalf("expected the second result to be selected, got:\n%s", view)
+	}
+
+	// The selection stays within the results.
+	b.update(keyDown)
+	b.update(keyPageDown)
+	if b.selected != 1 {
+		t.Fatalf("expected the last result to stay selected, got %d", b.selected)
+	}
+
+	b.update(keyHome)
+	if b.selected != 0 || b.offset != 0 {
+		t.Fatalf("expected the first result to be selected and visible, got selected %d, offset %d", b.selected, b.offset)
+	}
+
+	b.update(keyUp)
+	if b.selected != 0 {
+		t.Fatalf("expected the first result to stay selected, got %d", b.selected)
+	}
+
+	b.update(keyEnd)
+	if b.selected != 1 {
+		t.Fatalf("expected the last result to be selected, got %d", b.selected)
+	}
+
+	// The browser runs until it's quit.
+	buf.Reset()
+
+	err = runBrowser(context.Background(), strings.NewReader("kq"), bt, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !b.quit || b.selected != 0 {
+		t.Fatalf("expected the browser to quit with the first result selected, got quit %v, selected %d", b.quit, b.selected)
+	}
+
+	if !strings.HasSuffix(buf.String(), "\033[?1049l") {
+		t.Fatalf("expected the alternate screen to be restored, got %q", buf.String())
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"go/ast"
@@ -16,11 +17,24 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/picatz/taint"
 	"github.com/picatz/taint/callgraphutil"
+	envinjection "github.com/picatz/taint/env/injection"
+	execinjection "github.com/picatz/taint/exec/injection"
+	"github.com/picatz/taint/grpc/reflected"
+	loginjection "github.com/picatz/taint/log/injection"
+	redisinjection "github.com/picatz/taint/redis/injection"
+	"github.com/picatz/taint/report/mermaid"
+	"github.com/picatz/taint/secrets/leak"
+	sqlinjection "github.com/picatz/taint/sql/injection"
+	"github.com/picatz/taint/ssrf"
+	"github.com/picatz/taint/template/traversal"
+	"github.com/picatz/taint/xss"
 	"golang.org/x/term"
 	"golang.org/x/tools/go/callgraph"
 	"golang.org/x/tools/go/packages"
@@ -42,6 +56,18 @@ var (
 	ssaProg *ssa.Program
 	ssaPkgs []*ssa.Package
 	cg      *callgraph.Graph
+
+	// libraryParams are the parameters of a library's exported functions,
+	// which are checked as sources when loaded with --library.
+	libraryParams []*ssa.Parameter
+
+	// lastResults are the results of the last check, which can be
+	// browsed using the browse command.
+	lastResults taint.Results
+
+	// lastPanics are the panics recovered, and skipped, while loading and
+	// checking a program, which fail a non-interactive run (see runBatch).
+	lastPanics error
 )
 
 // highlightNode returns a string with the node highlighted, such that
@@ -116,6 +142,16 @@ func clearScreen(bt *bufio.Writer) error {
 	return nil
 }
 
+// writeProgress overwrites the current line with the given progress, such
+// as "built packages 3/10", followed by any additional details.
+func writeProgress(bt *bufio.Writer, desc string, done, total int, details ...string) {
+	bt.WriteString("\033[2K\033[0G" + desc + " " + styleNumber.Render(fmt.Sprintf("%d/%d", done, total)))
+	for _, detail := range details {
+		bt.WriteString(" " + detail)
+	}
+	bt.Flush()
+}
+
 type commandArg struct {
 	name     string
 	desc     string
@@ -125,14 +161,32 @@ type commandArg struct {
 type commandFlag struct {
 	name string
 	desc string
+
+	// isBool is true if the flag doesn't take a value, such as --library.
+	isBool bool
 }
 
 type command struct {
-	name  string
-	desc  string
-	args  []*commandArg
-	flags []*commandFlag
-	fn    commandFn
+	name     string
+	aliases  []string
+	desc     string
+	args     []*commandArg
+	flags    []*commandFlag
+	examples []string
+	fn       commandFn
+}
+
+// is returns true if the given name is the command's name, or one of its aliases.
+func (c *command) is(name string) bool {
+	if c.name == name {
+		return true
+	}
+	for _, alias := range c.aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *command) nRequiredArgs() int {
@@ -168,6 +222,45 @@ func (c *command) help() string {
 	return help.String()
 }
 
+// usage returns the detailed help for the command, including the
+// descriptions of its arguments and flags, and any examples.
+func (c *command) usage() string {
+	var usage strings.Builder
+
+	usage.WriteString(c.help())
+
+	if len(c.aliases) > 0 {
+		usage.WriteString("\n" + styleBold.Render("Aliases") + " " + styleCommand.Render(strings.Join(c.aliases, ", ")) + "\n")
+	}
+
+	if len(c.args) > 0 {
+		usage.WriteString("\n" + styleBold.Render("Arguments") + "\n\n")
+		for _, arg := range c.args {
+			usage.WriteString("  " + styleArgument.Render(fmt.Sprintf("<%s>", arg.name)) + " " + styleFaint.Render(arg.desc))
+			if arg.optional {
+				usage.WriteString(styleFaint.Render(" (optional)"))
+			}
+			usage.WriteString("\n")
+		}
+	}
+
+	if len(c.flags) > 0 {
+		usage.WriteString("\n" + styleBold.Render("Flags") + "\n\n")
+		for _, flag := range c.flags {
+			usage.WriteString("  " + styleFlag.Render(fmt.Sprintf("--%s", flag.name)) + " " + styleFaint.Render(flag.desc) + "\n")
+		}
+	}
+
+	if len(c.examples) > 0 {
+		usage.WriteString("\n" + styleBold.Render("Examples") + "\n\n")
+		for _, example := range c.examples {
+			usage.WriteString("  " + styleFaint.Render("> ") + example + "\n")
+		}
+	}
+
+	return usage.String()
+}
+
 type commandFn func(
 	ctx context.Context,
 	bt *bufio.Writer,
@@ -230,11 +323,30 @@ func (c commands) eval(ctx context.Context, bt *bufio.Writer, input string) erro
 
 	argsAndFlags := fields[1:]
 
+	// Find the command, to know which flags it accepts.
+	var cmd *command
+	for _, candidate := range c {
+		if candidate.is(cmdName) {
+			cmd = candidate
+			break
+		}
+	}
+
 	// Parse flags with Go's flag package.
 	flagSet := flag.NewFlagSet(cmdName, flag.ContinueOnError)
 
 	flagSet.SetOutput(bt)
 
+	if cmd != nil {
+		for _, f := range cmd.flags {
+			if f.isBool {
+				flagSet.Bool(f.name, false, f.desc)
+				continue
+			}
+			flagSet.String(f.name, "", f.desc)
+		}
+	}
+
 	flagSet.Usage = func() {
 		// Print command help.
 		bt.WriteString(c.help())
@@ -253,18 +365,16 @@ func (c commands) eval(ctx context.Context, bt *bufio.Writer, input string) erro
 		flags[f.Name] = f.Value.String()
 	})
 
-	for _, cmd := range c {
-		if cmd.name == cmdName {
-			// Check there are enough arguments.
-			if len(flagSet.Args()) < cmd.nRequiredArgs() {
-				bt.WriteString("not enough arguments, expected " + styleNumber.Render(fmt.Sprintf("%d", cmd.nRequiredArgs())) + " but got " + styleNumber.Render(fmt.Sprintf("%d", len(flagSet.Args()))) + "\n")
-				bt.WriteString("usage: " + cmd.help())
-				bt.Flush()
-				return nil
-			}
-
-			return cmd.fn(ctx, bt, flagSet.Args(), flags)
+	if cmd != nil {
+		// Check there are enough arguments.
+		if len(flagSet.Args()) < cmd.nRequiredArgs() {
+			bt.WriteString("not enough arguments, expected " + styleNumber.Render(fmt.Sprintf("%d", cmd.nRequiredArgs())) + " but got " + styleNumber.Render(fmt.Sprintf("%d", len(flagSet.Args()))) + "\n")
+			bt.WriteString("usage: " + cmd.help())
+			bt.Flush()
+			return nil
 		}
+
+		return cmd.fn(ctx, bt, flagSet.Args(), flags)
 	}
 
 	bt.WriteString("unknown command: " + cmdName + "\n")
@@ -274,14 +384,16 @@ func (c commands) eval(ctx context.Context, bt *bufio.Writer, input string) erro
 }
 
 var builtinCommandExit = &command{
-	name: "exit",
-	desc: "exit the shell",
-	fn:   errorCommandFn(io.EOF),
+	name:    "exit",
+	aliases: []string{"q", "quit"},
+	desc:    "exit the shell",
+	fn:      errorCommandFn(io.EOF),
 }
 
 var builtinCommandClear = &command{
-	name: "clear",
-	desc: "clear the screen",
+	name:    "clear",
+	aliases: []string{"cls"},
+	desc:    "clear the screen",
 	fn: terminalWriteFn(func(bt *bufio.Writer) error {
 		return clearScreen(bt)
 	}),
@@ -301,6 +413,40 @@ var builtinCommandLoad = &command{
 			optional: true,
 		},
 	},
+	flags: []*commandFlag{
+		{
+			name: "max-funcs",
+			desc: fmt.Sprintf("the number of source functions to load before only using the main package (default: %d, 0 for no limit)", defaultMaxFuncs),
+		},
+		{
+			name:   "clean",
+			desc:   "remove all cached repository clones before loading",
+			isBool: true,
+		},
+		{
+			name:   "timing",
+			desc:   "print how long each phase of loading took",
+			isBool: true,
+		},
+		{
+			name:   "library",
+			desc:   "load packages without a main function, using each exported function as a root, with its string parameters as sources",
+			isBool: true,
+		},
+		{
+			name: "name",
+			desc: "the name to select the target by, once other targets are loaded (default: the target)",
+		},
+	},
+	examples: []string{
+		"load ./cmd/taint/example",
+		"load https://github.com/picatz/taint ./...",
+		"load https://github.com/picatz/taint@main ./...",
+		"load --clean https://github.com/picatz/taint ./...",
+		"load --max-funcs 100000 ./...",
+		"load --library ./...",
+		"load --name svc1 ./svc1",
+	},
 	fn: func(ctx context.Context, bt *bufio.Writer, args []string, flags map[string]string) error {
 		arg := args[0]
 
@@ -316,6 +462,25 @@ var builtinCommandLoad = &command{
 			pattern = args[1]
 		}
 
+		maxFuncs := defaultMaxFuncs
+		if v, ok := flags["max-funcs"]; ok {
+			maxFuncs, err = strconv.Atoi(v)
+			if err != nil || maxFuncs < 0 {
+				bt.WriteString(fmt.Sprintf("invalid --max-funcs value %q\n", v))
+				bt.Flush()
+				return nil
+			}
+		}
+
+		if _, ok := flags["clean"]; ok {
+			if err := os.RemoveAll(cloneCacheDir()); err != nil {
+				bt.WriteString(err.Error() + "\n")
+				bt.Flush()
+				return nil
+			}
+			bt.WriteString(styleFaint.Render("removed cached clones from "+cloneCacheDir()) + "\n")
+		}
+
 		// If the argument starts with https://github.com/, then we'll try to
 		// clone the repository and load it.
 		if strings.HasPrefix(arg, "https://github.com/") {
@@ -356,14 +521,23 @@ var builtinCommandLoad = &command{
 			// packages.NeedExportFile |
 			// packages.NeedEmbedPatterns
 
-		// parseMode := parser.ParseComments
-		parseMode := parser.SkipObjectResolution
+		// Comments are parsed for directives, such as //taint:trusted.
+		parseMode := parser.SkipObjectResolution | parser.ParseComments
 
 		// patterns := []string{dir}
 		patterns := []string{pattern}
 		// patterns := []string{"all"}
 
-		pkgs, err = packages.Load(&packages.Config{
+		// Time each phase of loading, printed with --timing.
+		var timings []phaseTiming
+
+		start := time.Now()
+
+		// The program is only kept once it's loaded successfully, so a failed
+		// load doesn't replace the active target.
+		loaded := &target{}
+
+		loaded.pkgs, err = packages.Load(&packages.Config{
 			Mode:    loadMode,
 			Context: ctx,
 			Env:     os.Environ(),
@@ -379,68 +553,182 @@ var builtinCommandLoad = &command{
 			return nil
 		}
 
-		ssaBuildMode := ssa.InstantiateGenerics // ssa.SanityCheckFunctions | ssa.GlobalDebug
+		timings = append(timings, phaseTiming{"packages loaded", time.Since(start)})
 
-		// Analyze the package.
-		ssaProg, ssaPkgs = ssautil.Packages(pkgs, ssaBuildMode)
+		ssaBuildMode := ssa.InstantiateGenerics // ssa.SanityCheckFunctions | ssa.GlobalDebug
 
-		ssaProg.Build()
+		// Analyze the package, including any packages importing packages
+		// with errors, which are skipped.
+		var warnings taint.Warnings
+		start = time.Now()
+		loaded.ssaProg, loaded.ssaPkgs, warnings = taint.Packages(loaded.pkgs, ssaBuildMode)
 
-		for _, pkg := range ssaPkgs {
+		// Build each package (including dependencies), reporting progress
+		// since this can take a while for large programs.
+		allPkgs := loaded.ssaProg.AllPackages()
+		for i, pkg := range allPkgs {
 			pkg.Build()
+			writeProgress(bt, "built packages", i+1, len(allPkgs))
 		}
+		bt.WriteString("\n")
+		timings = append(timings, phaseTiming{"ssa built", time.Since(start)})
 
-		mainPkgs := ssautil.MainPackages(ssaPkgs)
+		// Warn about skipped packages, since any taint flowing through
+		// their functions is missed.
+		for _, warning := range warnings {
+			bt.WriteString(styleFaint.Render("warning: "+warning.String()) + "\n")
+		}
+
+		graphOpts := callgraphutil.GraphOptions{
+			Progress: func(done, total, nodes int) {
+				// Avoid writing to the terminal for every function.
+				if done%100 != 0 && done != total {
+					return
+				}
+				writeProgress(bt, "added functions", done, total, styleFaint.Render(fmt.Sprintf("(%d nodes)", nodes)))
+			},
+		}
 
-		mainFn := mainPkgs[0].Members["main"].(*ssa.Function)
+		start = time.Now()
 
-		var srcFns []*ssa.Function
+		if _, ok := flags["library"]; ok {
+			// Libraries have no main function, so each exported function
+			// is a root, called with parameters controlled by the caller.
+			entries, params := taint.LibraryEntryPoints(loaded.ssaPkgs)
+			if len(entries) == 0 {
+				bt.WriteString("no exported functions found\n")
+				bt.Flush()
+				return nil
+			}
 
-		for _, pkg := range ssaPkgs {
-			for _, fn := range pkg.Members {
-				if fn.Object() == nil {
-					continue
+			// Exported methods aren't package members, so they're added
+			// as source functions too, to include their calls.
+			srcFns := srcFuncs(loaded.ssaPkgs)
+			for _, entry := range entries {
+				if entry.Signature.Recv() != nil {
+					srcFns = append(srcFns, entry)
 				}
+			}
 
-				if fn.Object().Name() == "_" {
-					continue
-				}
+			loaded.cg, err = callgraphutil.NewMultiRootGraph(entries, graphOpts, srcFns...)
+			loaded.libraryParams = params
+		} else {
+			mainPkgs := ssautil.MainPackages(loaded.ssaPkgs)
+			if len(mainPkgs) == 0 {
+				bt.WriteString("no main function found (see --library)\n")
+				bt.Flush()
+				return nil
+			}
 
-				pkgFn := pkg.Func(fn.Object().Name())
-				if pkgFn == nil {
-					continue
-				}
+			mainFn := mainPkgs[0].Members["main"].(*ssa.Function)
 
-				var addAnons func(f *ssa.Function)
-				addAnons = func(f *ssa.Function) {
-					srcFns = append(srcFns, f)
-					for _, anon := range f.AnonFuncs {
-						addAnons(anon)
-					}
-				}
-				addAnons(pkgFn)
+			srcFns := srcFuncs(loaded.ssaPkgs)
+
+			// Constructing the callgraph for very large programs (e.g. a monorepo)
+			// can use too much memory, so only use the main package's functions
+			// past the threshold.
+			if boundedLoad(len(srcFns), maxFuncs) {
+				bt.WriteString(styleFaint.Render(fmt.Sprintf("found %d source functions, more than the maximum of %d, only using the main package (see --max-funcs)", len(srcFns), maxFuncs)) + "\n")
+				bt.Flush()
+				srcFns = srcFuncs(mainPkgs[:1])
+			}
+
+			loaded.cg, err = callgraphutil.NewGraphWithOptions(mainFn, graphOpts, srcFns...)
+		}
+		bt.WriteString("\n")
+		if err != nil {
+			// Functions which panicked while being added are skipped,
+			// but the rest of the callgraph can still be used.
+			var panicErr *callgraphutil.PanicError
+			if !errors.As(err, &panicErr) {
+				bt.WriteString(err.Error() + "\n")
+				bt.Flush()
+				return nil
 			}
+			bt.WriteString(styleFaint.Render("skipped functions: "+err.Error()) + "\n")
+			lastPanics = err
 		}
+		timings = append(timings, phaseTiming{"callgraph built", time.Since(start)})
 
-		if mainFn == nil {
-			bt.WriteString("no main function found\n")
-			bt.Flush()
-			return nil
+		if _, ok := flags["timing"]; ok {
+			writeTimings(bt, timings)
 		}
 
-		cg, err = callgraphutil.NewGraph(mainFn, srcFns...)
-		if err != nil {
-			bt.WriteString(err.Error() + "\n")
-			bt.Flush()
-			return nil
+		// Keep the loaded program, so it can be used again after loading others.
+		name := arg
+		if v, ok := flags["name"]; ok {
+			name = v
 		}
+		saveTarget(name, loaded)
 
-		bt.WriteString("loaded " + styleNumber.Render(fmt.Sprintf("%d", len(pkgs))) + " packages\n")
+		bt.WriteString("loaded " + styleNumber.Render(fmt.Sprintf("%d", len(loaded.pkgs))) + " packages\n")
 		bt.Flush()
 		return nil
 	},
 }
 
+// phaseTiming is how long a phase of a command took, such as loading
+// packages, which is printed with --timing.
+type phaseTiming struct {
+	phase    string
+	duration time.Duration
+}
+
+// writeTimings writes a line for each of the given timings, such as
+// "timing: packages loaded in 1.25s".
+func writeTimings(bt *bufio.Writer, timings []phaseTiming) {
+	for _, timing := range timings {
+		bt.WriteString(styleFaint.Render("timing: "+timing.phase+" in ") + styleNumber.Render(timing.duration.Round(time.Millisecond).String()) + "\n")
+	}
+}
+
+// defaultMaxFuncs is the default number of source functions to load before
+// only using the main package's functions to construct the callgraph.
+const defaultMaxFuncs = 50000
+
+// boundedLoad returns true if the number of source functions found is more
+// than the given maximum, which is unlimited if zero.
+func boundedLoad(funcs, maxFuncs int) bool {
+	return maxFuncs > 0 && funcs > maxFuncs
+}
+
+// srcFuncs returns the functions declared in the given packages, including
+// their anonymous functions.
+func srcFuncs(ssaPkgs []*ssa.Package) []*ssa.Function {
+	var srcFns []*ssa.Function
+
+	for _, pkg := range ssaPkgs {
+		if pkg == nil {
+			continue
+		}
+		for _, fn := range pkg.Members {
+			if fn.Object() == nil {
+				continue
+			}
+
+			if fn.Object().Name() == "_" {
+				continue
+			}
+
+			pkgFn := pkg.Func(fn.Object().Name())
+			if pkgFn == nil {
+				continue
+			}
+
+			var addAnons func(f *ssa.Function)
+			addAnons = func(f *ssa.Function) {
+				srcFns = append(srcFns, f)
+				for _, anon := range f.AnonFuncs {
+					addAnons(anon)
+				}
+			}
+			addAnons(pkgFn)
+		}
+	}
+
+	return srcFns
+}
+
 var builtinCommandPkgs = &command{
 	name: "pkgs",
 	desc: "list loaded packages",
@@ -563,14 +851,19 @@ var builtinCommandNodes = &command{
 }
 
 var builtinCommandsCallpath = &command{
-	name: "callpath",
-	desc: "find callpaths to a function",
+	name:    "callpath",
+	aliases: []string{"cp"},
+	desc:    "find callpaths to a function",
 	args: []*commandArg{
 		{
 			name: "function",
 			desc: "the function to find callpaths to",
 		},
 	},
+	examples: []string{
+		"callpath (*database/sql.DB).Query",
+		"callpath fmt.Println",
+	},
 	fn: func(ctx context.Context, bt *bufio.Writer, args []string, flags map[string]string) error {
 		if cg == nil {
 			bt.WriteString("no callgraph is loaded\n")
@@ -586,7 +879,13 @@ var builtinCommandsCallpath = &command{
 
 		fn := args[0]
 
-		paths := callgraphutil.PathsSearchCallTo(cg.Root, fn)
+		// The search stops once the command is canceled (Ctrl-C).
+		paths, err := callgraphutil.PathsSearchCallToContext(ctx, cg.Root, fn)
+		if err != nil {
+			bt.WriteString(styleFaint.Render("callpath canceled") + "\n")
+			bt.Flush()
+			return nil
+		}
 
 		if len(paths) == 0 {
 			bt.WriteString("no calls to " + fn + "\n")
@@ -613,6 +912,104 @@ var builtinCommandsCallpath = &command{
 	},
 }
 
+var builtinCommandCallees = &command{
+	name: "callees",
+	desc: "list the functions directly called by a function",
+	args: []*commandArg{
+		{
+			name: "function",
+			desc: "the function (or glob pattern) to list callees of",
+		},
+	},
+	examples: []string{
+		"callees main.main",
+		"callees *.handler",
+	},
+	fn: func(ctx context.Context, bt *bufio.Writer, args []string, flags map[string]string) error {
+		if cg == nil {
+			bt.WriteString("no callgraph is loaded\n")
+			bt.Flush()
+			return nil
+		}
+
+		if len(args) != 1 {
+			bt.WriteString("usage: callees <function>\n")
+			bt.Flush()
+			return nil
+		}
+
+		fn := args[0]
+
+		callees := callgraphutil.Callees(cg, fn)
+
+		if len(callees) == 0 {
+			bt.WriteString("no callees of " + fn + "\n")
+			bt.Flush()
+			return nil
+		}
+
+		for _, callee := range callees {
+			bt.WriteString(highlightNode(callee.String()) + "\n")
+		}
+		bt.Flush()
+		return nil
+	},
+}
+
+var builtinCommandFind = &command{
+	name: "find",
+	desc: "find callgraph nodes matching a pattern",
+	args: []*commandArg{
+		{
+			name: "pattern",
+			desc: "the fuzzy, glob (using *), or regular expression (using /.../) pattern to match functions",
+		},
+	},
+	examples: []string{
+		"find handler",
+		"find (*database/sql.DB).*",
+		"find /^main\\.(main|run)$/",
+	},
+	fn: func(ctx context.Context, bt *bufio.Writer, args []string, flags map[string]string) error {
+		if cg == nil {
+			bt.WriteString("no callgraph is loaded\n")
+			bt.Flush()
+			return nil
+		}
+
+		if len(args) != 1 {
+			bt.WriteString("usage: find <pattern>\n")
+			bt.Flush()
+			return nil
+		}
+
+		match, err := callgraphutil.NewMatcher(args[0])
+		if err != nil {
+			bt.WriteString(err.Error() + "\n")
+			bt.Flush()
+			return nil
+		}
+
+		nodes := callgraphutil.FindNodes(cg, match)
+
+		if len(nodes) == 0 {
+			bt.WriteString("no nodes match " + args[0] + "\n")
+			bt.Flush()
+			return nil
+		}
+
+		for _, node := range nodes {
+			bt.WriteString(highlightNode(node.String()))
+			if pos := node.Func.Prog.Fset.Position(node.Func.Pos()); pos.IsValid() {
+				bt.WriteString(" " + pos.String())
+			}
+			bt.WriteString("\n")
+		}
+		bt.Flush()
+		return nil
+	},
+}
+
 var builtinCommandCheck = &command{
 	name: "check",
 	desc: "perform a taint analysis check",
@@ -623,9 +1020,56 @@ var builtinCommandCheck = &command{
 		},
 		{
 			name: "sink",
-			desc: "the sink to check",
+			desc: "the sink to check, or a comma-separated list of sinks",
+		},
+	},
+	flags: []*commandFlag{
+		{
+			name:   "verbose",
+			desc:   "render each hop of the paths on its own line, with its position",
+			isBool: true,
+		},
+		{
+			name:   "group-by-sink",
+			desc:   "print each sink once, followed by the paths reaching it",
+			isBool: true,
+		},
+		{
+			name: "format",
+			desc: "the output format, either text (default) or mermaid",
+		},
+		{
+			name:   "timing",
+			desc:   "print how long the check took",
+			isBool: true,
+		},
+		{
+			name:   "reflect",
+			desc:   "treat values set using reflection as tainted by the value set (approximate)",
+			isBool: true,
+		},
+		{
+			name: "from",
+			desc: "only check sources within functions reachable from the matching entry function (exact or glob)",
+		},
+		{
+			name: "sink-pkg",
+			desc: "only check sinks within the given comma-separated packages, or their subpackages",
+		},
+		{
+			name: "no-sink-pkg",
+			desc: "ignore sinks within the given comma-separated packages, or their subpackages",
 		},
 	},
+	examples: []string{
+		"check *net/http.Request (*database/sql.DB).Query",
+		"check --verbose *net/http.Request (*database/sql.DB).Query",
+		"check --group-by-sink *net/http.Request (*database/sql.DB).Query",
+		"check --format mermaid *net/http.Request (*database/sql.DB).Query",
+		"check --reflect *net/http.Request (*database/sql.DB).Query",
+		"check --from main.handler *net/http.Request (*database/sql.DB).Query",
+		"check --sink-pkg database/sql *net/http.Request (*database/sql.DB).Query,(*gorm.io/gorm.DB).Raw",
+	},
 	fn: func(ctx context.Context, bt *bufio.Writer, args []string, flags map[string]string) error {
 		if cg == nil {
 			bt.WriteString("no callgraph is loaded\n")
@@ -641,42 +1085,563 @@ var builtinCommandCheck = &command{
 
 		source := args[0]
 
-		sink := args[1]
-
-		results := taint.Check(cg, taint.NewSources(source), taint.NewSinks(sink))
+		var sinks taint.Sinks
+		sinks.Set(args[1])
 
-		var resultsStr strings.Builder
+		// Filter the sinks by their package, before searching for paths to them.
+		sinks = filterSinkPackages(sinks, flags["sink-pkg"], flags["no-sink-pkg"])
+		if len(sinks) == 0 {
+			bt.WriteString("no sinks remain after filtering by package\n")
+			bt.Flush()
+			return nil
+		}
 
-		for _, result := range results {
-			resultPathStr := result.Path.String()
+		format := flags["format"]
+		switch format {
+		case "", "text", "mermaid":
+		default:
+			bt.WriteString(fmt.Sprintf("unknown --format value %q, expected text or mermaid\n", format))
+			bt.Flush()
+			return nil
+		}
 
-			parts := strings.Split(resultPathStr, " → ")
+		// Scope the check to the functions reachable from the entry function.
+		var scope map[*ssa.Function]bool
+		if from, ok := flags["from"]; ok {
+			reachable := callgraphutil.Reachable(cg, from)
+			if len(reachable) == 0 {
+				bt.WriteString(fmt.Sprintf("no functions match --from %q\n", from))
+				bt.Flush()
+				return nil
+			}
 
-			for i, part := range parts {
-				parts[i] = highlightNode(part)
+			scope = map[*ssa.Function]bool{}
+			for _, n := range reachable {
+				scope[n.Func] = true
 			}
+		}
 
-			resultPathStr = strings.Join(parts, styleFaint.Render(" → "))
+		start := time.Now()
 
-			resultsStr.WriteString(resultPathStr + "\n")
+		_, reflect := flags["reflect"]
+
+		results, err := taint.CheckContextWithOptions(ctx, cg, taint.NewSources(source), sinks, taint.Options{
+			Parameters: libraryParams,
+			Reflect:    reflect,
+			Scope:      scope,
+		})
+
+		lastResults = results
+
+		if _, ok := flags["timing"]; ok {
+			writeTimings(bt, []phaseTiming{{"check ran", time.Since(start)}})
+		}
+		if ctx.Err() != nil {
+			bt.WriteString(styleFaint.Render("check canceled, showing partial results") + "\n")
+		} else if err != nil {
+			bt.WriteString(styleFaint.Render("skipped paths: "+err.Error()) + "\n")
 		}
 
-		bt.WriteString(resultsStr.String())
-		bt.Flush()
-		return nil
-	},
-}
+		if format == "mermaid" {
+			// Render the results as markdown, to embed in documents.
+			if err := mermaid.Write(bt, results); err != nil {
+				bt.WriteString(err.Error() + "\n")
+			}
+			bt.Flush()
+			return nil
+		}
 
-var builtinCommands = commands{
+		_, verbose := flags["verbose"]
+
+		var resultsStr strings.Builder
+
+		if _, ok := flags["group-by-sink"]; ok {
+			for _, group := range groupBySink(results) {
+				resultsStr.WriteString(sinkHeader(group[0]))
+				for _, result := range group {
+					resultsStr.WriteString(indent(resultPath(result.Path, verbose), "\t"))
+					resultsStr.WriteString(styleFaint.Render(fmt.Sprintf("\t\tsource: %s", result.SourceName)) + "\n")
+				}
+			}
+		} else {
+			for _, result := range results {
+				resultsStr.WriteString(resultPath(result.Path, verbose))
+				resultsStr.WriteString(styleFaint.Render(fmt.Sprintf("\tsource: %s, sink: %s", result.SourceName, result.SinkName)) + "\n")
+			}
+		}
+
+		resultsStr.WriteString(styleFaint.Render(checkSummary(results)) + "\n")
+
+		bt.WriteString(resultsStr.String())
+		bt.Flush()
+		return nil
+	},
+}
+
+// resultPath returns the given result path on a single line, with its
+// nodes highlighted, or with each hop on its own line if verbose.
+func resultPath(path callgraphutil.Path, verbose bool) string {
+	if verbose {
+		return verbosePath(path)
+	}
+
+	parts := strings.Split(path.String(), " → ")
+
+	for i, part := range parts {
+		parts[i] = highlightNode(part)
+	}
+
+	return strings.Join(parts, styleFaint.Render(" → ")) + "\n"
+}
+
+// sinkPackage returns the package path of the given sink function, which
+// uses SSA function name syntax, e.g. "database/sql" for "(*database/sql.DB).Query".
+func sinkPackage(sink string) string {
+	name := strings.TrimLeft(sink, "(*")
+	if i := strings.Index(name, ")"); i >= 0 {
+		name = name[:i]
+	}
+
+	// The package path ends at the first dot after its last slash,
+	// since the path's elements may contain dots, e.g. "gorm.io/gorm".
+	slash := strings.LastIndex(name, "/")
+	if i := strings.Index(name[slash+1:], "."); i >= 0 {
+		return name[:slash+1+i]
+	}
+	return name
+}
+
+// inPackages returns true if the package path is one of the given
+// comma-separated packages, or one of their subpackages.
+func inPackages(pkg, pkgs string) bool {
+	for _, p := range strings.Split(pkgs, ",") {
+		p = strings.TrimSpace(p)
+		if p != "" && (pkg == p || strings.HasPrefix(pkg, p+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSinkPackages returns the sinks within the include packages, if any
+// are given, and not within the exclude packages (see check --sink-pkg).
+func filterSinkPackages(sinks taint.Sinks, include, exclude string) taint.Sinks {
+	filtered := taint.NewSinks()
+	for sink := range sinks {
+		pkg := sinkPackage(sink)
+		if include != "" && !inPackages(pkg, include) {
+			continue
+		}
+		if exclude != "" && inPackages(pkg, exclude) {
+			continue
+		}
+		filtered[sink] = struct{}{}
+	}
+	return filtered
+}
+
+// groupBySink groups the given results by their sink value, in the order
+// each sink was first found, such that each group shares the same sink.
+func groupBySink(results taint.Results) []taint.Results {
+	var groups []taint.Results
+
+	index := map[ssa.Value]int{}
+	for _, result := range results {
+		i, ok := index[result.SinkValue]
+		if !ok {
+			i = len(groups)
+			index[result.SinkValue] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], result)
+	}
+
+	return groups
+}
+
+// sinkHeader returns the line introducing a group of results for the
+// same sink, such as "sink: (*database/sql.DB).Query (main.go:42:15)".
+func sinkHeader(result taint.Result) string {
+	header := styleBold.Render("sink: ") + result.SinkName
+	if site := result.Path.Last().Site; site != nil {
+		if pos := result.Path.Last().Callee.Func.Prog.Fset.Position(site.Pos()); pos.IsValid() {
+			header += " " + styleFaint.Render("("+pos.String()+")")
+		}
+	}
+	return header + "\n"
+}
+
+// indent returns the given lines with the given prefix added to each.
+func indent(lines, prefix string) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(lines, "\n") {
+		if line == "" {
+			continue
+		}
+		b.WriteString(prefix + line)
+	}
+	return b.String()
+}
+
+// verbosePath returns the given path with each hop on its own indented
+// line, such as "→ n4:(*database/sql.DB).Query (main.go:42:15)", with the
+// position of the call made by the hop when it is available.
+func verbosePath(path callgraphutil.Path) string {
+	var b strings.Builder
+
+	if len(path) == 0 {
+		return ""
+	}
+
+	root := path[0].Caller
+	b.WriteString(highlightNode(root.String()))
+	if pos := root.Func.Prog.Fset.Position(root.Func.Pos()); pos.IsValid() {
+		b.WriteString(" " + styleFaint.Render("("+pos.String()+")"))
+	}
+	b.WriteString("\n")
+
+	for i, edge := range path {
+		b.WriteString(strings.Repeat("  ", i+1) + styleFaint.Render("→ ") + highlightNode(edge.Callee.String()))
+		if edge.Site != nil {
+			if pos := edge.Callee.Func.Prog.Fset.Position(edge.Site.Pos()); pos.IsValid() {
+				b.WriteString(" " + styleFaint.Render("("+pos.String()+")"))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// checkSummary returns a summary of the given check results, such as
+// "3 findings across 2 sinks, 2 high / 1 medium confidence", where each
+// sink is a unique sink call, and duplicate findings are only counted once.
+func checkSummary(results taint.Results) string {
+	results = taint.Dedupe(results)
+
+	sinks := map[ssa.Value]struct{}{}
+	confidence := map[taint.Confidence]int{}
+	for _, result := range results {
+		sinks[result.SinkValue] = struct{}{}
+		confidence[result.Confidence]++
+	}
+
+	summary := plural(len(results), "finding") + " across " + plural(len(sinks), "sink")
+
+	var levels []string
+	for _, level := range []taint.Confidence{taint.ConfidenceHigh, taint.ConfidenceMedium} {
+		if confidence[level] > 0 {
+			levels = append(levels, fmt.Sprintf("%d %s", confidence[level], level))
+		}
+	}
+	if len(levels) > 0 {
+		summary += ", " + strings.Join(levels, " / ") + " confidence"
+	}
+
+	return summary
+}
+
+// plural returns the given count followed by the word, which is
+// pluralized (with a trailing "s") unless the count is one.
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+var builtinCommandCoverage = &command{
+	name:    "coverage",
+	aliases: []string{"cov"},
+	desc:    "list sources and whether they reach a sink",
+	args: []*commandArg{
+		{
+			name: "source",
+			desc: "the source to check",
+		},
+		{
+			name: "sink",
+			desc: "the sink to check",
+		},
+	},
+	examples: []string{
+		"coverage *net/http.Request (*database/sql.DB).Query",
+	},
+	fn: func(ctx context.Context, bt *bufio.Writer, args []string, flags map[string]string) error {
+		if cg == nil {
+			bt.WriteString("no callgraph is loaded\n")
+			bt.Flush()
+			return nil
+		}
+
+		if len(args) != 2 {
+			bt.WriteString("usage: coverage <source> <sink>\n")
+			bt.Flush()
+			return nil
+		}
+
+		source := args[0]
+
+		sink := args[1]
+
+		coverage, err := taint.CoverageContext(ctx, cg, taint.NewSources(source), taint.NewSinks(sink))
+		if ctx.Err() != nil {
+			bt.WriteString(styleFaint.Render("coverage canceled") + "\n")
+			bt.Flush()
+			return nil
+		}
+
+		var coverageStr strings.Builder
+
+		for _, c := range coverage {
+			if c.Reached() {
+				coverageStr.WriteString(c.String() + "\n")
+				continue
+			}
+			coverageStr.WriteString(styleFaint.Render(c.String()) + "\n")
+		}
+		if err != nil {
+			coverageStr.WriteString(styleFaint.Render("skipped paths: "+err.Error()) + "\n")
+		}
+
+		bt.WriteString(coverageStr.String())
+		bt.Flush()
+		return nil
+	},
+}
+
+// batchRules are the built-in rules run by the batch command, in the
+// order their findings are printed.
+var batchRules = []struct {
+	name string
+	run  func(ctx context.Context, cg *callgraph.Graph) (taint.Results, error)
+}{
+	{"sqli", sqlinjection.RunContext},
+	{"xss", xss.RunContext},
+	{"logi", loginjection.RunContext},
+	{"cmdi", execinjection.RunContext},
+	{"redisi", redisinjection.RunContext},
+	{"ssrf", ssrf.RunContext},
+	{"envi", envinjection.RunContext},
+	{"secretleak", leak.RunContext},
+	{"reflected", reflected.RunContext},
+	{"tmplpath", traversal.RunContext},
+}
+
+var builtinCommandBatch = &command{
+	name: "batch",
+	desc: "run the built-in rules, printing the findings of each rule separately",
+	args: []*commandArg{
+		{
+			name:     "rules",
+			desc:     "the names of the rules to run (default all)",
+			optional: true,
+		},
+	},
+	examples: []string{
+		"batch",
+		"batch sqli xss logi",
+	},
+	fn: func(ctx context.Context, bt *bufio.Writer, args []string, flags map[string]string) error {
+		if cg == nil {
+			bt.WriteString("no callgraph is loaded\n")
+			bt.Flush()
+			return nil
+		}
+
+		known := map[string]bool{}
+		for _, rule := range batchRules {
+			known[rule.name] = true
+		}
+
+		selected := map[string]bool{}
+		for _, name := range args {
+			if !known[name] {
+				bt.WriteString(fmt.Sprintf("unknown rule %q\n", name))
+				bt.Flush()
+				return nil
+			}
+			selected[name] = true
+		}
+
+		var (
+			resultsStr strings.Builder
+			all        taint.Results
+		)
+
+		for _, rule := range batchRules {
+			if len(selected) > 0 && !selected[rule.name] {
+				continue
+			}
+
+			// Stop running rules if the command was interrupted.
+			if ctx.Err() != nil {
+				resultsStr.WriteString(styleFaint.Render("batch canceled, showing partial results") + "\n")
+				break
+			}
+
+			results, err := rule.run(ctx, cg)
+			all = append(all, results...)
+
+			resultsStr.WriteString(styleBold.Render(rule.name) + " " + styleFaint.Render("("+plural(len(results), "finding")+")") + "\n")
+			for _, result := range results {
+				resultsStr.WriteString(indent(resultPath(result.Path, false), "\t"))
+				resultsStr.WriteString(styleFaint.Render(fmt.Sprintf("\t\tsource: %s, sink: %s", result.SourceName, result.SinkName)) + "\n")
+			}
+
+			// The rule stops checking paths once the command is interrupted,
+			// returning the results found so far.
+			if ctx.Err() != nil {
+				resultsStr.WriteString(styleFaint.Render("batch canceled, showing partial results") + "\n")
+				break
+			}
+			if err != nil {
+				resultsStr.WriteString(styleFaint.Render("\tskipped paths: "+err.Error()) + "\n")
+				lastPanics = errors.Join(lastPanics, err)
+			}
+		}
+
+		lastResults = all
+
+		resultsStr.WriteString(styleFaint.Render(checkSummary(all)) + "\n")
+
+		bt.WriteString(resultsStr.String())
+		bt.Flush()
+		return nil
+	},
+}
+
+var builtinCommandHelp = &command{
+	name:    "help",
+	aliases: []string{"h", "?"},
+	desc:    "print help for all commands, or a specific command",
+	args: []*commandArg{
+		{
+			name:     "command",
+			desc:     "the command to print detailed help for",
+			optional: true,
+		},
+	},
+	examples: []string{
+		"help",
+		"help check",
+	},
+	// fn is set in init, since it refers to builtinCommands.
+}
+
+func init() {
+	builtinCommandHelp.fn = func(ctx context.Context, bt *bufio.Writer, args []string, flags map[string]string) error {
+		if len(args) == 0 {
+			bt.WriteString(builtinCommands.help())
+			bt.Flush()
+			return nil
+		}
+
+		for _, cmd := range builtinCommands {
+			if cmd.is(args[0]) {
+				bt.WriteString(cmd.usage())
+				bt.Flush()
+				return nil
+			}
+		}
+
+		bt.WriteString("unknown command: " + args[0] + "\n")
+		bt.Flush()
+		return nil
+	}
+}
+
+var builtinCommands = commands{
+	builtinCommandHelp,
 	builtinCommandExit,
 	builtinCommandClear,
 	builtinCommandLoad,
+	builtinCommandUse,
 	builtinCommandPkgs,
 	builtinCommandCG,
 	builtinCommandRoot,
 	builtinCommandNodes,
 	builtinCommandsCallpath,
+	builtinCommandCallees,
+	builtinCommandFind,
 	builtinCommandCheck,
+	builtinCommandCoverage,
+	builtinCommandBatch,
+	builtinCommandBrowse,
+}
+
+// lineReader reads lines of input, such as a *term.Terminal.
+type lineReader interface {
+	ReadLine() (string, error)
+}
+
+// continuedLine returns the given line without a trailing "\", which is
+// used to continue the input on the next line, and whether it continues.
+func continuedLine(line string) (string, bool) {
+	trimmed := strings.TrimRight(line, " \t")
+	if !strings.HasSuffix(trimmed, "\\") {
+		return line, false
+	}
+	return strings.TrimSuffix(trimmed, "\\"), true
+}
+
+// readInput reads a logical line of input, joining lines continued with a
+// trailing "\", and lines pasted together (using bracketed paste mode),
+// with a space between each line.
+func readInput(r lineReader, bt *bufio.Writer) (string, error) {
+	var lines []string
+	for {
+		line, err := r.ReadLine()
+		pasted := err == term.ErrPasteIndicator
+		if err != nil && !pasted {
+			return "", err
+		}
+
+		line, continued := continuedLine(line)
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+
+		if !continued && !pasted {
+			return strings.Join(lines, " "), nil
+		}
+
+		// Show a continuation prompt for the next line, unless it
+		// is being pasted.
+		if !pasted {
+			bt.WriteString("\033[0G" + styleFaint.Render(". "))
+			bt.Flush()
+		}
+	}
+}
+
+// commandContext returns a context for running a single command, which is
+// canceled when an interrupt signal is received (Ctrl-C), without canceling
+// the given parent context used by the shell.
+//
+// Ctrl-C only sends an interrupt signal outside of raw mode, so if stdin is
+// a terminal, raw mode is left (using the given restore function) while the
+// command runs, and entered again by the returned done function. The signal
+// handler is installed before leaving, and removed after entering raw mode,
+// so Ctrl-C never exits the shell.
+func commandContext(ctx context.Context, restore func()) (context.Context, func() error, error) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+
+	if !term.IsTerminal(0) {
+		return ctx, func() error { stop(); return nil }, nil
+	}
+
+	rawState, err := term.GetState(0)
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+	restore()
+
+	return ctx, func() error {
+		defer stop()
+		return term.Restore(0, rawState)
+	}, nil
 }
 
 func startShell(ctx context.Context) error {
@@ -692,6 +1657,11 @@ func startShell(ctx context.Context) error {
 	// Use buffered terminal writer.
 	bt := bufio.NewWriter(t)
 
+	// Enable bracketed paste mode, so pasted blocks can be
+	// handled as a single logical line of input.
+	t.SetBracketedPasteMode(true)
+	defer t.SetBracketedPasteMode(false)
+
 	// Autocomplete for commands.
 	t.AutoCompleteCallback = func(line string, pos int, key rune) (newLine string, newPos int, ok bool) {
 		// If the user presses tab, then autocomplete the command.
@@ -754,6 +1724,12 @@ func startShell(ctx context.Context) error {
 					return "load " + dir, len("load " + dir), true
 				}
 
+				// If the line is an alias, then autocomplete the
+				// canonical command name.
+				if line != cmd.name && cmd.is(line) {
+					return cmd.name, len(cmd.name), true
+				}
+
 				if strings.HasPrefix(cmd.name, line) {
 					// Return the new line and position, which must come after the
 					// command.
@@ -785,14 +1761,28 @@ func startShell(ctx context.Context) error {
 		// Flush the buffer to the terminal.
 		bt.Flush()
 
-		// Read up to line from STDIN.
-		input, err := t.ReadLine()
+		// Read a logical line of input from STDIN, which may
+		// span multiple lines when continued or pasted.
+		input, err := readInput(t, bt)
+		if err != nil {
+			return err
+		}
+
+		// Leave raw mode while the command runs, so Ctrl-C sends an
+		// interrupt signal which only cancels the command, instead of
+		// exiting the shell (which Ctrl-C at the prompt does).
+		cmdCtx, done, err := commandContext(ctx, restore)
 		if err != nil {
 			return err
 		}
 
 		// Evaluate the input.
-		err = builtinCommands.eval(ctx, bt, input)
+		err = builtinCommands.eval(cmdCtx, bt, input)
+
+		if rawErr := done(); rawErr != nil {
+			return rawErr
+		}
+
 		if err != nil {
 			return err
 		}
@@ -802,9 +1792,89 @@ func startShell(ctx context.Context) error {
 	}
 }
 
+// Exit codes of the non-interactive batch mode (see runBatch), so CI can
+// tell a failure to run the analysis apart from the findings of one.
+const (
+	exitClean    = 0
+	exitFindings = 1
+	exitError    = 2
+)
+
+// runBatch loads the target given as the first argument, then runs the
+// built-in rules given as the remaining arguments (default all), like the
+// batch command, returning the exit code for the outcome.
+func runBatch(ctx context.Context, w io.Writer, args []string) (code int) {
+	bt := bufio.NewWriter(w)
+
+	// Unexpected panics are internal errors, not findings.
+	defer func() {
+		if r := recover(); r != nil {
+			bt.WriteString(fmt.Sprintf("error: panic: %v\n", r))
+			bt.Flush()
+			code = exitError
+		}
+	}()
+
+	if len(args) == 0 {
+		bt.WriteString("usage: taint <target> [rules...]\n")
+		bt.Flush()
+		return exitError
+	}
+
+	known := map[string]bool{}
+	for _, rule := range batchRules {
+		known[rule.name] = true
+	}
+	for _, name := range args[1:] {
+		if !known[name] {
+			bt.WriteString(fmt.Sprintf("error: unknown rule %q\n", name))
+			bt.Flush()
+			return exitError
+		}
+	}
+
+	// The callgraph is only set once the target is loaded successfully,
+	// and no other targets are kept, since only this one is checked.
+	cg = nil
+	targets = map[string]*target{}
+	lastPanics = nil
+
+	err := builtinCommandLoad.fn(ctx, bt, args[:1], map[string]string{})
+	if err != nil || cg == nil {
+		bt.WriteString(fmt.Sprintf("error: failed to load %q\n", args[0]))
+		bt.Flush()
+		return exitError
+	}
+
+	err = builtinCommandBatch.fn(ctx, bt, args[1:], map[string]string{})
+	if err != nil || ctx.Err() != nil {
+		return exitError
+	}
+
+	// Skipped functions or paths may hide findings, so the results
+	// can't be trusted to be complete.
+	if lastPanics != nil {
+		return exitError
+	}
+
+	if len(lastResults) > 0 {
+		return exitFindings
+	}
+	return exitClean
+}
+
 func main() {
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
-	defer cancel()
+	// Interrupts (Ctrl-C) only cancel the running command, see commandContext.
+	ctx := context.Background()
+
+	// Run non-interactively when given a target, such as in CI, where an
+	// interrupt stops the rules, printing the findings so far.
+	if len(os.Args) > 1 {
+		batchCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		code := runBatch(batchCtx, os.Stdout, os.Args[1:])
+		stop()
+		os.Exit(code)
+	}
 
 	if err := startShell(ctx); err != nil {
 		if err == io.EOF {
@@ -816,28 +1886,71 @@ func main() {
 	}
 }
 
-// cloneRepository clones a repository and returns the directory it was cloned
-// to using go-git under the hood, which is a pure Go implementation of Git.
-func cloneRepository(ctx context.Context, repoURL string) (string, string, error) {
+// repoRef is a GitHub repository, and the ref (e.g. branch or tag) to load,
+// given as a URL such as https://github.com/picatz/taint@v0.1.0.
+type repoRef struct {
+	owner, repo, ref string
+}
+
+// parseRepoURL parses the given GitHub repository URL, with an optional
+// "@ref" suffix. Without a ref, the repository's default branch is used.
+func parseRepoURL(repoURL string) (repoRef, error) {
 	// Parse the repository URL (e.g. https://github.com/picatz/taint).
 	u, err := url.Parse(repoURL)
 	if err != nil {
-		return "", "", fmt.Errorf("%w", err)
+		return repoRef{}, fmt.Errorf("%w", err)
 	}
 
 	// Split the path into segments.
 	pathSegments := strings.Split(u.Path, "/")
 
 	// Ensure there are at least 2 segments for owner and repo.
-	if len(pathSegments) < 3 {
-		return "", "", fmt.Errorf("invalid GitHub URL: %s", repoURL)
+	if len(pathSegments) < 3 || pathSegments[1] == "" || pathSegments[2] == "" {
+		return repoRef{}, fmt.Errorf("invalid GitHub URL: %s", repoURL)
+	}
+
+	repo, ref, _ := strings.Cut(pathSegments[2], "@")
+
+	return repoRef{
+		owner: pathSegments[1],
+		repo:  strings.TrimSuffix(repo, ".git"),
+		ref:   ref,
+	}, nil
+}
+
+// url returns the URL to clone the repository from.
+func (r repoRef) url() string {
+	return "https://github.com/" + r.owner + "/" + r.repo
+}
+
+// cacheKey returns the key of the repository within the clone cache, such
+// as "picatz/taint@v0.1.0", so different refs of a repository can coexist.
+func (r repoRef) cacheKey() string {
+	ref := r.ref
+	if ref == "" {
+		ref = "HEAD"
 	}
+	return r.owner + "/" + r.repo + "@" + ref
+}
 
-	// Get the owner and repo part of the URL.
-	ownerAndRepo := pathSegments[1] + "/" + pathSegments[2]
+// cloneCacheDir returns the directory repositories are cloned to.
+func cloneCacheDir() string {
+	return filepath.Join(os.TempDir(), "taint", "github")
+}
+
+// cloneRepository clones a repository and returns the directory it was cloned
+// to using go-git under the hood, which is a pure Go implementation of Git.
+//
+// Clones are cached by repository and ref, so loading the same ref again
+// reuses the existing clone (see load --clean).
+func cloneRepository(ctx context.Context, repoURL string) (string, string, error) {
+	r, err := parseRepoURL(repoURL)
+	if err != nil {
+		return "", "", err
+	}
 
 	// Get the directory path.
-	dir := filepath.Join(os.TempDir(), "taint", "github", ownerAndRepo)
+	dir := filepath.Join(cloneCacheDir(), filepath.FromSlash(r.cacheKey()))
 
 	// Check if the directory exists.
 	_, err = os.Stat(dir)
@@ -858,14 +1971,30 @@ func cloneRepository(ctx context.Context, repoURL string) (string, string, error
 		return dir, head.Hash().String(), nil
 	}
 
-	// Clone the repository.
-	repo, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
-		URL:          repoURL,
-		Depth:        1,
-		Tags:         git.NoTags,
-		SingleBranch: true,
-	})
+	clone := func(refName plumbing.ReferenceName) (*git.Repository, error) {
+		return git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+			URL:           r.url(),
+			ReferenceName: refName,
+			Depth:         1,
+			Tags:          git.NoTags,
+			SingleBranch:  true,
+		})
+	}
+
+	// Clone the repository, trying the ref as a branch, then as a tag.
+	var repo *git.Repository
+	if r.ref == "" {
+		repo, err = clone("")
+	} else {
+		repo, err = clone(plumbing.NewBranchReferenceName(r.ref))
+		if err != nil && ctx.Err() == nil {
+			os.RemoveAll(dir)
+			repo, err = clone(plumbing.NewTagReferenceName(r.ref))
+		}
+	}
 	if err != nil {
+		// Remove the partial clone, so it isn't mistaken for a cached one.
+		os.RemoveAll(dir)
 		return dir, "", fmt.Errorf("%w", err)
 	}
 
@@ -0,0 +1,961 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/callgraphutil"
+	"golang.org/x/term"
+	"golang.org/x/tools/go/callgraph"
+)
+
+// fakeLines is a lineReader returning the given lines (and errors) in order.
+type fakeLines []struct {
+	line string
+	err  error
+}
+
+func (f *fakeLines) ReadLine() (string, error) {
+	if len(*f) == 0 {
+		return "", io.EOF
+	}
+	next := (*f)[0]
+	*f = (*f)[1:]
+	return next.line, next.err
+}
+
+func TestReadInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines fakeLines
+		want  string
+	}{
+		{
+			name: "single line",
+			lines: fakeLines{
+				{line: "check *net/http.Request (*database/sql.DB).Query"},
+			},
+			want: "check *net/http.Request (*database/sql.DB).Query",
+		},
+		{
+			name: "continued lines",
+			lines: fakeLines{
+				{line: `check \`},
+				{line: `  *net/http.Request \`},
+				{line: "  (*database/sql.DB).Query"},
+			},
+			want: "check *net/http.Request (*database/sql.DB).Query",
+		},
+		{
+			name: "pasted lines",
+			lines: fakeLines{
+				{line: "callpath", err: term.ErrPasteIndicator},
+				{line: "(*database/sql.DB).Query", err: term.ErrPasteIndicator},
+				{line: ""},
+			},
+			want: "callpath (*database/sql.DB).Query",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := readInput(&test.lines, bufio.NewWriter(io.Discard))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestReadInputEOF(t *testing.T) {
+	lines := fakeLines{
+		{line: `check \`},
+	}
+
+	_, err := readInput(&lines, bufio.NewWriter(io.Discard))
+	if err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestHelpCommand(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "help check")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"check", "<source>", "the source to check", "<sink>", "the sink to check", "check *net/http.Request (*database/sql.DB).Query"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected help to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestCommandAliases(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "q")
+	if err != io.EOF {
+		t.Fatalf("expected the exit command to return io.EOF, got %v", err)
+	}
+
+	err = builtinCommands.eval(context.Background(), bt, "cp fmt.Println")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "unknown command") {
+		t.Fatalf("expected cp to invoke the callpath command, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "help cp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "the function to find callpaths to") {
+		t.Fatalf("expected help for the callpath command, got:\n%s", buf.String())
+	}
+}
+
+func TestCommandContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cmdCtx, done, err := commandContext(ctx, func() {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer done()
+
+	// Interrupt the process, as if Ctrl-C was pressed while a command runs.
+	err = syscall.Kill(os.Getpid(), syscall.SIGINT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-cmdCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the command context to be canceled")
+	}
+
+	if ctx.Err() != nil {
+		t.Fatal("expected the shell context to not be canceled")
+	}
+}
+
+func TestCheckSummary(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load ./example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check *net/http.Request (*database/sql.DB).Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "1 finding across 1 sink") {
+		t.Fatalf("expected summary of the findings, got:\n%s", buf.String())
+	}
+
+	if !strings.Contains(buf.String(), "source: *net/http.Request, sink: (*database/sql.DB).Query") {
+		t.Fatalf("expected the matched source and sink names, got:\n%s", buf.String())
+	}
+}
+
+func TestCalleesCommand(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load ./example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "callees *.main$1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), ".business") {
+		t.Fatalf("expected the handler's callees to include business, got:\n%s", buf.String())
+	}
+}
+
+func TestFindCommand(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load ./example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, `find /\.(business|handle)$/`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 matching nodes, got %d:\n%s", len(lines), buf.String())
+	}
+
+	for i, want := range []string{".business", ".handle"} {
+		if !strings.Contains(lines[i], want) || !strings.Contains(lines[i], "main.go:") {
+			t.Errorf("expected line %d to contain %q and its position, got %q", i, want, lines[i])
+		}
+	}
+}
+
+func TestBoundedLoad(t *testing.T) {
+	tests := []struct {
+		funcs, maxFuncs int
+		want            bool
+	}{
+		{funcs: 10, maxFuncs: defaultMaxFuncs, want: false},
+		{funcs: defaultMaxFuncs, maxFuncs: defaultMaxFuncs, want: false},
+		{funcs: defaultMaxFuncs + 1, maxFuncs: defaultMaxFuncs, want: true},
+		{funcs: 1000000, maxFuncs: 0, want: false},
+		{funcs: 2, maxFuncs: 1, want: true},
+	}
+
+	for _, test := range tests {
+		if got := boundedLoad(test.funcs, test.maxFuncs); got != test.want {
+			t.Errorf("boundedLoad(%d, %d) = %v, want %v", test.funcs, test.maxFuncs, got, test.want)
+		}
+	}
+}
+
+func TestLoadMaxFuncs(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load --max-funcs 1 ./example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "only using the main package") {
+		t.Fatalf("expected a warning about the maximum number of functions, got:\n%s", buf.String())
+	}
+
+	if cg == nil {
+		t.Fatal("expected a callgraph to be loaded")
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "load --max-funcs nope ./example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "invalid --max-funcs") {
+		t.Fatalf("expected an invalid flag error, got:\n%s", buf.String())
+	}
+}
+
+func TestLoadWarnings(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load ../../testdata/partial")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "warning: github.com/picatz/taint/testdata/partial/store: package not analyzed") {
+		t.Fatalf("expected a warning about the skipped package, got:\n%s", buf.String())
+	}
+
+	if cg == nil {
+		t.Fatal("expected a callgraph to be loaded")
+	}
+}
+
+func TestLoadLibrary(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load --library ../../testdata/library")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cg == nil {
+		t.Fatalf("expected a callgraph to be loaded, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check *net/http.Request (*database/sql.DB).Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "source: github.com/picatz/taint/testdata/library.FindUser parameter name") {
+		t.Fatalf("expected the exported function's parameter as the source, got:\n%s", buf.String())
+	}
+
+	// Without --library, there is no main function to use as the root.
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "load ../../testdata/library")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "no main function found") {
+		t.Fatalf("expected no main function to be found, got:\n%s", buf.String())
+	}
+}
+
+func TestLoadLibraryTrusted(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load --library ../../testdata/library")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check *net/http.Request (*database/sql.DB).Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The table parameter of FindTable is annotated with //taint:trusted.
+	if strings.Contains(buf.String(), "FindTable") {
+		t.Fatalf("expected the trusted parameter to not be a source, got:\n%s", buf.String())
+	}
+
+	if !strings.Contains(buf.String(), "1 finding across 1 sink") {
+		t.Fatalf("expected only the untrusted parameter to be found, got:\n%s", buf.String())
+	}
+}
+
+func TestCheckVerbose(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load ./example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check --verbose *net/http.Request (*database/sql.DB).Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hops int
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if !strings.Contains(line, "→ ") {
+			continue
+		}
+		hops++
+		if !strings.Contains(line, "main.go:") {
+			t.Errorf("expected the hop's position, got %q", line)
+		}
+	}
+
+	if hops == 0 {
+		t.Fatalf("expected each hop on its own line, got:\n%s", buf.String())
+	}
+
+	if !strings.Contains(buf.String(), "1 finding across 1 sink") {
+		t.Fatalf("expected summary of the findings, got:\n%s", buf.String())
+	}
+}
+
+func TestCheckGroupBySink(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load ../../testdata/fanin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check --group-by-sink *net/http.Request (*database/sql.DB).Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+
+	// The sink is printed once, followed by a path from each handler.
+	if n := strings.Count(out, "sink: (*database/sql.DB).Query"); n != 1 {
+		t.Fatalf("expected the sink to be printed once, got %d:\n%s", n, out)
+	}
+
+	lines := strings.Split(out, "\n")
+
+	var sinkLine int
+	for i, line := range lines {
+		if strings.Contains(line, "sink: ") {
+			sinkLine = i
+			break
+		}
+	}
+
+	var paths []string
+	for _, line := range lines[sinkLine+1:] {
+		if strings.HasPrefix(line, "\t") && strings.Contains(line, "→") {
+			paths = append(paths, line)
+		}
+	}
+
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 indented paths beneath the sink, got %d:\n%s", len(paths), out)
+	}
+
+	for _, handler := range []string{"adminHandler", "userHandler"} {
+		if !strings.Contains(paths[0]+paths[1], handler) {
+			t.Errorf("expected a path through %s, got:\n%s", handler, out)
+		}
+	}
+
+	if !strings.Contains(out, "2 findings across 1 sink") {
+		t.Fatalf("expected summary of the findings, got:\n%s", out)
+	}
+}
+
+func TestRepoCacheKey(t *testing.T) {
+	tests := []struct {
+		url, key, cloneURL string
+	}{
+		{
+			url:      "https://github.com/picatz/taint",
+			key:      "picatz/taint@HEAD",
+			cloneURL: "https://github.com/picatz/taint",
+		},
+		{
+			url:      "https://github.com/picatz/taint@v0.1.0",
+			key:      "picatz/taint@v0.1.0",
+			cloneURL: "https://github.com/picatz/taint",
+		},
+		{
+			url:      "https://github.com/picatz/taint.git@main",
+			key:      "picatz/taint@main",
+			cloneURL: "https://github.com/picatz/taint",
+		},
+		{
+			url:      "https://github.com/picatz/taint/tree/main",
+			key:      "picatz/taint@HEAD",
+			cloneURL: "https://github.com/picatz/taint",
+		},
+	}
+
+	for _, test := range tests {
+		r, err := parseRepoURL(test.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.cacheKey(); got != test.key {
+			t.Errorf("cache key for %q = %q, want %q", test.url, got, test.key)
+		}
+		if got := r.url(); got != test.cloneURL {
+			t.Errorf("clone URL for %q = %q, want %q", test.url, got, test.cloneURL)
+		}
+	}
+
+	for _, invalid := range []string{"https://github.com/picatz", "https://github.com/"} {
+		if _, err := parseRepoURL(invalid); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestCheckFormatMermaid(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load ./example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check --format mermaid *net/http.Request (*database/sql.DB).Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"```mermaid", "graph LR", `"(*database/sql.DB).Query"`, "source -.->"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check --format nope *net/http.Request (*database/sql.DB).Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), `unknown --format value "nope"`) {
+		t.Fatalf("expected an unknown format error, got:\n%s", buf.String())
+	}
+}
+
+func TestTiming(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load --timing ./example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, phase := range []string{"packages loaded in", "ssa built in", "callgraph built in"} {
+		if !strings.Contains(buf.String(), "timing: "+phase) {
+			t.Errorf("expected a timing line for %q, got:\n%s", phase, buf.String())
+		}
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check --timing *net/http.Request (*database/sql.DB).Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "timing: check ran in") {
+		t.Fatalf("expected a timing line for the check, got:\n%s", buf.String())
+	}
+
+	// Without --timing, no timings are printed.
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check *net/http.Request (*database/sql.DB).Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "timing:") {
+		t.Fatalf("expected no timing lines, got:\n%s", buf.String())
+	}
+}
+
+func TestBatch(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load ../../testdata/batch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "batch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+
+	// Each rule has its own section, with its number of findings.
+	for _, header := range []string{
+		"sqli (1 finding)\n",
+		"xss (1 finding)\n",
+		"logi (1 finding)\n",
+		"cmdi (0 findings)\n",
+	} {
+		if !strings.Contains(out, header) {
+			t.Errorf("expected header %q, got:\n%s", header, out)
+		}
+	}
+
+	// Sections are printed in order, followed by their findings.
+	sqli, xss, logi := strings.Index(out, "sqli ("), strings.Index(out, "xss ("), strings.Index(out, "logi (")
+	if !(sqli < xss && xss < logi) {
+		t.Errorf("expected sections in rule order, got:\n%s", out)
+	}
+	if query := strings.Index(out, "sink: (*database/sql.DB).Query"); query < sqli || query > xss {
+		t.Errorf("expected the sql finding in the sqli section, got:\n%s", out)
+	}
+
+	if !strings.Contains(out, "3 findings across 3 sinks, 3 high confidence") {
+		t.Errorf("expected total findings, got:\n%s", out)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "batch xss")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out := buf.String(); strings.Contains(out, "sqli (") || !strings.Contains(out, "xss (1 finding)") {
+		t.Errorf("expected only the xss section, got:\n%s", out)
+	}
+}
+
+func TestCheckFrom(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load ../../testdata/fanin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check --from *.adminHandler *net/http.Request (*database/sql.DB).Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "1 finding across 1 sink") {
+		t.Fatalf("expected only the finding from the admin handler, got:\n%s", buf.String())
+	}
+
+	if len(lastResults) != 1 || !strings.HasSuffix(lastResults[0].EntryFunc, ".adminHandler") {
+		t.Fatalf("expected the finding from the admin handler, got %v", lastResults)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check --from main.missing *net/http.Request (*database/sql.DB).Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), `no functions match --from "main.missing"`) {
+		t.Fatalf("expected no matching functions, got:\n%s", buf.String())
+	}
+}
+
+func TestCheckSinkPkg(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load ../../testdata/sinkpkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const sinks = "(*database/sql.DB).Query,(*github.com/picatz/taint/testdata/sinkpkg/gorm.DB).Raw"
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check *net/http.Request "+sinks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lastResults) != 2 {
+		t.Fatalf("expected findings for both sinks, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check --sink-pkg database/sql *net/http.Request "+sinks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lastResults) != 1 || lastResults[0].SinkName != "(*database/sql.DB).Query" {
+		t.Fatalf("expected only the database/sql finding, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check --no-sink-pkg database/sql *net/http.Request "+sinks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lastResults) != 1 || !strings.HasSuffix(lastResults[0].SinkName, "gorm.DB).Raw") {
+		t.Fatalf("expected only the gorm finding, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "check --sink-pkg net/http *net/http.Request "+sinks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "no sinks remain after filtering by package") {
+		t.Fatalf("expected no sinks to remain, got:\n%s", buf.String())
+	}
+}
+
+func TestRunBatchExitCodes(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"clean", []string{"../../testdata/clean"}, exitClean},
+		{"findings", []string{"../../testdata/batch"}, exitFindings},
+		{"findings for a rule", []string{"../../testdata/batch", "sqli"}, exitFindings},
+		{"missing target", []string{"../../testdata/missing"}, exitError},
+		{"unknown rule", []string{"../../testdata/batch", "nope"}, exitError},
+		{"no target", nil, exitError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			code := runBatch(context.Background(), &buf, tc.args)
+			if code != tc.code {
+				t.Fatalf("expected exit code %d, got %d:\n%s", tc.code, code, buf.String())
+			}
+		})
+	}
+}
+
+func TestRunBatchSummary(t *testing.T) {
+	var buf bytes.Buffer
+
+	code := runBatch(context.Background(), &buf, []string{"../../testdata/confidence", "sqli"})
+	if code != exitFindings {
+		t.Fatalf("expected exit code %d, got %d:\n%s", exitFindings, code, buf.String())
+	}
+
+	out := buf.String()
+
+	// The summary counts each finding printed, one of which is only
+	// found through a call of a function stored in a struct field.
+	if findings := strings.Count(out, "source: "); findings != 2 {
+		t.Fatalf("expected 2 findings, got %d:\n%s", findings, out)
+	}
+
+	if !strings.Contains(out, "2 findings across 2 sinks, 1 high / 1 medium confidence") {
+		t.Errorf("expected summary of the findings, got:\n%s", out)
+	}
+}
+
+func TestBatchCanceled(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load ../../testdata/batch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := batchRules
+	defer func() { batchRules = rules }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Interrupt the command while the first rule is running, as if Ctrl-C
+	// was pressed, which stops the rule before it checks any paths.
+	batchRules = append(batchRules[:0:0], batchRules...)
+	run := batchRules[0].run
+	batchRules[0].run = func(ctx context.Context, cg *callgraph.Graph) (taint.Results, error) {
+		cancel()
+		return run(ctx, cg)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(ctx, bt, "batch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+
+	if !strings.Contains(out, batchRules[0].name+" (0 findings)") || !strings.Contains(out, "batch canceled") {
+		t.Fatalf("expected the batch to be canceled during the first rule, got:\n%s", out)
+	}
+	if strings.Contains(out, batchRules[1].name+" (") {
+		t.Fatalf("expected no rules to run after the batch was canceled, got:\n%s", out)
+	}
+}
+
+func TestRunBatchPanics(t *testing.T) {
+	rules := batchRules
+	defer func() { batchRules = rules }()
+
+	// Replace the first rule with one which skips a panicking path.
+	batchRules = append(batchRules[:0:0], batchRules[0])
+	batchRules[0].run = func(ctx context.Context, cg *callgraph.Graph) (taint.Results, error) {
+		return nil, &callgraphutil.PanicError{Value: "unexpected"}
+	}
+
+	var buf bytes.Buffer
+
+	code := runBatch(context.Background(), &buf, []string{"../../testdata/clean", batchRules[0].name})
+	if code != exitError {
+		t.Fatalf("expected exit code %d, got %d:\n%s", exitError, code, buf.String())
+	}
+}
+
+func TestUse(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load --name fanin ../../testdata/fanin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = builtinCommands.eval(context.Background(), bt, "load --name clean ../../testdata/clean")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The clean target only uses parameterized queries, which are
+	// found by the check command, but not the sqli rule.
+	const check = "batch sqli"
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, check)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lastResults) != 0 {
+		t.Fatalf("expected no findings for the clean target, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "use fanin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = builtinCommands.eval(context.Background(), bt, check)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lastResults) != 2 {
+		t.Fatalf("expected a finding for each fanin handler, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "use")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "* fanin") || !strings.Contains(buf.String(), "  clean") {
+		t.Fatalf("expected both targets listed, with fanin active, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "use clean")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lastResults) != 0 {
+		t.Fatalf("expected the clean target's results to be restored, got %v", lastResults)
+	}
+
+	buf.Reset()
+
+	err = builtinCommands.eval(context.Background(), bt, "use missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), `no target named "missing" is loaded`) {
+		t.Fatalf("expected a missing target, got:\n%s", buf.String())
+	}
+}
+
+func TestLoadFailureKeepsTarget(t *testing.T) {
+	var buf bytes.Buffer
+
+	bt := bufio.NewWriter(&buf)
+
+	err := builtinCommands.eval(context.Background(), bt, "load --name fanin ../../testdata/fanin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = builtinCommands.eval(context.Background(), bt, "batch sqli")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loadedCG, results := cg, lastResults
+
+	// Loading a program without a main function fails, which must
+	// not replace the active target, or lose its results.
+	err = builtinCommands.eval(context.Background(), bt, "load --name broken ../../testdata/library")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if activeTarget != "fanin" || cg != loadedCG {
+		t.Fatalf("expected fanin to remain the active target, got %q:\n%s", activeTarget, buf.String())
+	}
+
+	if _, ok := targets["broken"]; ok {
+		t.Fatal("expected the failed load not to be kept as a target")
+	}
+
+	if len(lastResults) != len(results) {
+		t.Fatalf("expected the fanin results to be kept, got %v", lastResults)
+	}
+}
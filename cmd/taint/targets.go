@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/picatz/taint"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+)
+
+// target is a loaded program, which is kept after loading other programs,
+// so it can be selected again using the use command.
+type target struct {
+	pkgs          []*packages.Package
+	ssaProg       *ssa.Program
+	ssaPkgs       []*ssa.Package
+	cg            *callgraph.Graph
+	libraryParams []*ssa.Parameter
+	lastResults   taint.Results
+}
+
+var (
+	// targets are the loaded programs, by name (see load --name).
+	targets = map[string]*target{}
+
+	// activeTarget is the name of the target the other commands operate
+	// on, which is stored in the package level variables, such as cg.
+	activeTarget string
+)
+
+// saveTarget stores the given loaded program as the target with the given
+// name, making it the active target.
+func saveTarget(name string, t *target) {
+	keepLastResults()
+	targets[name] = t
+	activate(name, t)
+}
+
+// keepLastResults stores the results of the last check for the active
+// target, before another target is used or loaded.
+func keepLastResults() {
+	if t, ok := targets[activeTarget]; ok {
+		t.lastResults = lastResults
+	}
+}
+
+// useTarget makes the target with the given name the active program,
+// returning false if there isn't a target with that name.
+func useTarget(name string) bool {
+	t, ok := targets[name]
+	if !ok {
+		return false
+	}
+
+	keepLastResults()
+	activate(name, t)
+
+	return true
+}
+
+// activate stores the given target in the package level variables
+// the other commands operate on, such as cg.
+func activate(name string, t *target) {
+	pkgs, ssaProg, ssaPkgs, cg = t.pkgs, t.ssaProg, t.ssaPkgs, t.cg
+	libraryParams, lastResults = t.libraryParams, t.lastResults
+	activeTarget = name
+}
+
+var builtinCommandUse = &command{
+	name: "use",
+	desc: "select the loaded target other commands operate on, or list the loaded targets",
+	args: []*commandArg{
+		{
+			name:     "name",
+			desc:     "the name of the target to use (see load --name)",
+			optional: true,
+		},
+	},
+	examples: []string{
+		"use",
+		"use svc1",
+	},
+	fn: func(ctx context.Context, bt *bufio.Writer, args []string, flags map[string]string) error {
+		if len(args) == 0 {
+			if len(targets) == 0 {
+				bt.WriteString("no targets are loaded\n")
+				bt.Flush()
+				return nil
+			}
+
+			names := make([]string, 0, len(targets))
+			for name := range targets {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				if name == activeTarget {
+					bt.WriteString(styleBold.Render("* "+name) + "\n")
+					continue
+				}
+				bt.WriteString("  " + name + "\n")
+			}
+			bt.Flush()
+			return nil
+		}
+
+		if !useTarget(args[0]) {
+			bt.WriteString(fmt.Sprintf("no target named %q is loaded\n", args[0]))
+			bt.Flush()
+			return nil
+		}
+
+		bt.WriteString("using " + styleNumber.Render(args[0]) + "\n")
+		bt.Flush()
+		return nil
+	},
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/picatz/taint/template/traversal"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(traversal.Analyzer)
+}
@@ -0,0 +1,129 @@
+package taint
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/ssa"
+)
+
+// SourceCoverage describes a source matched within a callgraph, and the
+// sinks it reaches, if any.
+type SourceCoverage struct {
+	// Source is the matched source type or function.
+	Source string
+	// Value is the SSA value where the source was matched, which
+	// is either a function parameter, or a call to a function.
+	Value ssa.Value
+	// Sinks are the unique sinks reached by the source.
+	Sinks []string
+}
+
+// Reached returns true if the source reached any sink.
+func (c SourceCoverage) Reached() bool {
+	return len(c.Sinks) > 0
+}
+
+// String returns a human readable representation of the source coverage.
+func (c SourceCoverage) String() string {
+	str := fmt.Sprintf("%s: %s (%s)", c.Value.Parent(), c.Source, c.Value.Name())
+
+	if !c.Reached() {
+		return str + ": no sink reached"
+	}
+
+	return str + ": reached " + strings.Join(c.Sinks, ", ")
+}
+
+// Coverage lists all of the sources matched within the callgraph, and whether
+// each of them reaches any of the given sinks. This can be used to understand
+// the coverage of an analysis, such as identifying tainted inputs which are
+// never used in a dangerous way.
+//
+// Sources are matched as the parameters of functions in the callgraph with
+// a source type, and calls to source functions.
+func Coverage(cg *callgraph.Graph, sources Sources, sinks Sinks) []SourceCoverage {
+	coverage, _ := CoverageContext(context.Background(), cg, sources, sinks)
+	return coverage
+}
+
+// CoverageContext is like Coverage, but stops checking which sinks are
+// reached once the given context is canceled, returning the context's error,
+// along with any paths which panicked while being checked (see CheckContext).
+func CoverageContext(ctx context.Context, cg *callgraph.Graph, sources Sources, sinks Sinks) ([]SourceCoverage, error) {
+	var coverage []SourceCoverage
+
+	for _, fn := range sortedFuncs(cg) {
+		for _, param := range fn.Params {
+			if src, ok := sources.includes(param.Type().String()); ok {
+				coverage = append(coverage, SourceCoverage{Source: src, Value: param})
+			}
+		}
+
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(*ssa.Call)
+				if !ok {
+					continue
+				}
+				if src, ok := sources.includes(call.Call.Value.String()); ok {
+					coverage = append(coverage, SourceCoverage{Source: src, Value: call})
+				}
+			}
+		}
+	}
+
+	// Identify the sinks reached from each source function, using
+	// the function where the source value was found in each result.
+	type origin struct {
+		fn  *ssa.Function
+		src string
+	}
+
+	reached := map[origin]map[string]struct{}{}
+
+	results, err := CheckContext(ctx, cg, sources, sinks)
+
+	for _, result := range results {
+		if result.SourceValue == nil || result.SourceValue.Parent() == nil {
+			continue
+		}
+
+		o := origin{fn: result.SourceValue.Parent(), src: result.SourceType}
+		if reached[o] == nil {
+			reached[o] = map[string]struct{}{}
+		}
+		reached[o][result.Path.Last().Callee.Func.String()] = struct{}{}
+	}
+
+	for i, c := range coverage {
+		for sink := range reached[origin{fn: c.Value.Parent(), src: c.Source}] {
+			coverage[i].Sinks = append(coverage[i].Sinks, sink)
+		}
+		sort.Strings(coverage[i].Sinks)
+	}
+
+	return coverage, err
+}
+
+// sortedFuncs returns the functions of the callgraph's nodes, sorted by
+// their string representation to provide a stable ordering.
+func sortedFuncs(cg *callgraph.Graph) []*ssa.Function {
+	fns := make([]*ssa.Function, 0, len(cg.Nodes))
+
+	for fn := range cg.Nodes {
+		if fn == nil {
+			continue
+		}
+		fns = append(fns, fn)
+	}
+
+	sort.Slice(fns, func(i, j int) bool {
+		return fns[i].String() < fns[j].String()
+	})
+
+	return fns
+}
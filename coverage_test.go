@@ -0,0 +1,69 @@
+package taint_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/picatz/taint"
+)
+
+func TestCoverage(t *testing.T) {
+	cg := loadCallGraph(t, "sqli")
+
+	coverage := taint.Coverage(
+		cg,
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+	)
+
+	reached := map[string]bool{}
+
+	for _, c := range coverage {
+		reached[c.Value.Parent().Name()] = c.Reached()
+
+		if c.Value.Parent().Name() == "health" && !strings.HasSuffix(c.String(), "no sink reached") {
+			t.Errorf("expected %q to report no sink reached", c)
+		}
+	}
+
+	for fn, want := range map[string]bool{
+		"search": true,
+		"lookup": true,
+		"health": false,
+	} {
+		got, ok := reached[fn]
+		if !ok {
+			t.Errorf("expected source in %q to be matched", fn)
+			continue
+		}
+		if got != want {
+			t.Errorf("expected source in %q reached to be %v, got %v", fn, want, got)
+		}
+	}
+}
+
+func TestCoverageContextCanceled(t *testing.T) {
+	cg := loadCallGraph(t, "sqli")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	coverage, err := taint.CoverageContext(
+		ctx,
+		cg,
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected coverage to be canceled, got %v", err)
+	}
+
+	// Sources are still matched, but no sinks are reached.
+	for _, c := range coverage {
+		if c.Reached() {
+			t.Errorf("expected %q to not be reached", c)
+		}
+	}
+}
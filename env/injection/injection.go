@@ -0,0 +1,120 @@
+package injection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/callgraphutil"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/callgraph"
+)
+
+var userControlledValues = taint.NewSources(
+	"*net/http.Request",
+)
+
+var environmentFunctions = taint.NewSinks(
+	// Note: at this time, they *must* be a function or method.
+	"os.Setenv",
+	"syscall.Setenv",
+	"golang.org/x/sys/unix.Setenv",
+)
+
+// Rule is the taint rule for environment variable injection, where user
+// controlled data is written to the process environment, which is inherited
+// by child processes (e.g. LD_PRELOAD or PATH), and can also be run directly
+// using taint.Run alongside other rules.
+var Rule = taint.NewRule(
+	"envi",
+	"potential environment injection",
+	userControlledValues,
+	environmentFunctions,
+	nil,
+)
+
+// Analyzer finds potential environment variable injection issues.
+var Analyzer = &analysis.Analyzer{
+	Name:     "envi",
+	Doc:      "finds potential environment variable injection issues",
+	Run:      run,
+	Requires: []*analysis.Analyzer{buildssa.Analyzer},
+}
+
+// extraSources, extraSinks, and extraSanitizers are set using the analyzer's flags.
+var (
+	extraSources    taint.Sources
+	extraSinks      taint.Sinks
+	extraSanitizers taint.Sanitizers
+)
+
+func init() {
+	taint.RegisterFlags(&Analyzer.Flags, &extraSources, &extraSinks, &extraSanitizers)
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	// Require the os, syscall, or golang.org/x/sys/unix package is imported
+	// in the program being analyzed before running the analysis.
+	//
+	// This prevents wasting time analyzing programs that don't
+	// modify their environment.
+	if len(extraSinks) == 0 && !taint.Imports(pass.Pkg, "os", "syscall", "golang.org/x/sys/unix") {
+		return nil, nil
+	}
+
+	// Get the built SSA IR.
+	buildSSA := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+
+	// Identify the main function from the package's SSA IR.
+	mainFn := buildSSA.Pkg.Func("main")
+	if mainFn == nil {
+		return nil, nil
+	}
+
+	// Construct a callgraph, using the main function as the root,
+	// constructed of all other functions. This returns a callgraph
+	// we can use to identify directed paths to environment changes.
+	cg, err := callgraphutil.NewGraph(mainFn, buildSSA.SrcFuncs...)
+	if err != nil {
+		// Functions which panicked while being added to the callgraph
+		// are skipped, continuing with the rest of the program.
+		var panicErr *callgraphutil.PanicError
+		if !errors.As(err, &panicErr) {
+			return nil, fmt.Errorf("failed to create new callgraph: %w", err)
+		}
+	}
+
+	// Paths which panicked while being checked are skipped.
+	results, _ := Run(cg)
+
+	for _, result := range results {
+		pass.Reportf(result.SinkValue.Pos(), "%s", result.Message)
+	}
+
+	return nil, nil
+}
+
+// Run performs the analyzer's environment injection check on the given callgraph.
+func Run(cg *callgraph.Graph) (taint.Results, error) {
+	return RunContext(context.Background(), cg)
+}
+
+// RunContext is like Run, but stops checking once the given context is
+// canceled, returning the context's error.
+func RunContext(ctx context.Context, cg *callgraph.Graph) (taint.Results, error) {
+	// Run the environment injection rule for user controlled values
+	// (sources) ending up in environment functions (sinks), including
+	// any additional sources and sinks given using flags.
+	rule := taint.NewRule(
+		Rule.Name(),
+		Rule.Message(),
+		Rule.Sources().Union(extraSources),
+		Rule.Sinks().Union(extraSinks),
+		Rule.Sanitizers().Union(extraSanitizers),
+	)
+
+	return taint.RunContext(ctx, cg, rule)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"os/exec"
+)
+
+func setProxy(proxy string) {
+	os.Setenv("HTTP_PROXY", proxy) // want "potential environment injection"
+}
+
+func main() {
+	http.HandleFunc("/env", func(w http.ResponseWriter, r *http.Request) {
+		os.Setenv(r.URL.Query().Get("k"), r.URL.Query().Get("v")) // want "potential environment injection"
+		exec.Command("env").Run()
+	})
+
+	http.HandleFunc("/proxy", func(w http.ResponseWriter, r *http.Request) {
+		setProxy(r.FormValue("proxy"))
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		os.Setenv("MODE", "safe")
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,5 @@
+package unix
+
+func Setenv(key, value string) error {
+	return nil
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"net/http"
+
+	"golang.org/x/sys/unix"
+)
+
+func main() {
+	http.HandleFunc("/env", func(w http.ResponseWriter, r *http.Request) {
+		unix.Setenv("PATH", r.FormValue("path")) // want "potential environment injection"
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
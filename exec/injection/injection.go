@@ -0,0 +1,182 @@
+package injection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/constant"
+	"path"
+	"strings"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/callgraphutil"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/ssa"
+)
+
+var userControlledValues = taint.NewSources(
+	"*net/http.Request",
+)
+
+var injectableExecFunctions = taint.NewSinks(
+	// Note: at this time, they *must* be a function or method.
+	"os/exec.Command",
+	"os/exec.CommandContext",
+	"os.StartProcess",
+)
+
+// shells are programs which interpret their arguments as shell commands
+// when given the "-c" flag, e.g. exec.Command("sh", "-c", input).
+var shells = map[string]struct{}{
+	"sh":   {},
+	"bash": {},
+	"zsh":  {},
+}
+
+// Analyzer finds potential command injection issues to demonstrate
+// the github.com/picatz/taint package.
+var Analyzer = &analysis.Analyzer{
+	Name:     "cmdi",
+	Doc:      "finds potential command injection issues",
+	Run:      run,
+	Requires: []*analysis.Analyzer{buildssa.Analyzer},
+}
+
+// extraSources, extraSinks, and extraSanitizers are set using the analyzer's flags.
+var (
+	extraSources    taint.Sources
+	extraSinks      taint.Sinks
+	extraSanitizers taint.Sanitizers
+)
+
+func init() {
+	taint.RegisterFlags(&Analyzer.Flags, &extraSources, &extraSinks, &extraSanitizers)
+}
+
+// startsProcess returns true if the package uses os.StartProcess, since
+// most programs import the os package without running commands.
+func startsProcess(pass *analysis.Pass) bool {
+	for _, obj := range pass.TypesInfo.Uses {
+		if obj.Pkg() != nil && obj.Pkg().Path() == "os" && obj.Name() == "StartProcess" {
+			return true
+		}
+	}
+	return false
+}
+
+// constString returns the string value of the given value, if it is
+// a string constant.
+func constString(v ssa.Value) (string, bool) {
+	c, ok := v.(*ssa.Const)
+	if !ok || c.Value == nil || c.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(c.Value), true
+}
+
+// shellCommand returns true if the given exec.Command call runs a known
+// shell with the "-c" flag, followed by a non-constant command string.
+func shellCommand(call *ssa.CallCommon) bool {
+	args := call.Args
+	if strings.HasSuffix(call.Value.String(), "CommandContext") {
+		args = args[1:]
+	}
+	if len(args) != 2 {
+		return false
+	}
+
+	name, ok := constString(args[0])
+	if !ok {
+		return false
+	}
+	if _, ok := shells[path.Base(name)]; !ok {
+		return false
+	}
+
+	cmdArgs := taint.VariadicArgs(args[1])
+	for i := int64(0); i < int64(len(cmdArgs)); i++ {
+		if flag, ok := constString(cmdArgs[i]); ok && flag == "-c" {
+			next, ok := cmdArgs[i+1]
+			if !ok {
+				return false
+			}
+			_, isConst := constString(next)
+			return !isConst
+		}
+	}
+
+	return false
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	// Require the os/exec package is imported, or os.StartProcess is used,
+	// in the program being analyzed before running the analysis.
+	//
+	// This prevents wasting time analyzing programs that don't run commands.
+	if len(extraSinks) == 0 && !taint.Imports(pass.Pkg, "os/exec") && !startsProcess(pass) {
+		return nil, nil
+	}
+
+	// Get the built SSA IR.
+	buildSSA := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+
+	// Identify the main function from the package's SSA IR.
+	mainFn := buildSSA.Pkg.Func("main")
+	if mainFn == nil {
+		return nil, nil
+	}
+
+	// Construct a callgraph, using the main function as the root,
+	// constructed of all other functions. This returns a callgraph
+	// we can use to identify directed paths to exec functions.
+	cg, err := callgraphutil.NewGraph(mainFn, buildSSA.SrcFuncs...)
+	if err != nil {
+		// Functions which panicked while being added to the callgraph
+		// are skipped, continuing with the rest of the program.
+		var panicErr *callgraphutil.PanicError
+		if !errors.As(err, &panicErr) {
+			return nil, fmt.Errorf("failed to create new callgraph: %w", err)
+		}
+	}
+
+	// Paths which panicked while being checked are skipped.
+	results, _ := Run(cg)
+
+	for _, result := range results {
+		pass.Reportf(result.SinkValue.Pos(), "%s", result.Message)
+	}
+
+	return nil, nil
+}
+
+// Run performs the analyzer's command injection check on the given callgraph.
+func Run(cg *callgraph.Graph) (taint.Results, error) {
+	return RunContext(context.Background(), cg)
+}
+
+// RunContext is like Run, but stops checking once the given context is
+// canceled, returning the context's error.
+func RunContext(ctx context.Context, cg *callgraph.Graph) (taint.Results, error) {
+	// Run taint check for user controlled values (sources) ending
+	// up in injectable exec functions (sinks).
+	results, err := taint.CheckContextWithOptions(ctx, cg, userControlledValues.Union(extraSources), injectableExecFunctions.Union(extraSinks), taint.Options{
+		Sanitizers: extraSanitizers,
+	})
+
+	for i, result := range results {
+		results[i].Rule = "cmdi"
+
+		// Commands interpreted by a shell are far more dangerous than
+		// tainted arguments passed directly to a program (argv).
+		if shellCommand(result.Path.Last().Site.Common()) {
+			results[i].Message = "potential shell command injection"
+			continue
+		}
+		results[i].Message = "potential command injection"
+	}
+
+	return results, err
+}
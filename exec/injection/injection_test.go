@@ -0,0 +1,17 @@
+package injection
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+var testdata = analysistest.TestData()
+
+func TestA(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "a")
+}
+
+func TestStartProcess(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "startprocess")
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http"
+	"os/exec"
+)
+
+func main() {
+	http.HandleFunc("/shell", func(w http.ResponseWriter, r *http.Request) {
+		exec.Command("bash", "-c", r.FormValue("cmd")).Run() // want "potential shell command injection"
+	})
+
+	http.HandleFunc("/shell-context", func(w http.ResponseWriter, r *http.Request) {
+		exec.CommandContext(r.Context(), "/bin/sh", "-c", "echo "+r.FormValue("msg")).Run() // want "potential shell command injection"
+	})
+
+	http.HandleFunc("/argv", func(w http.ResponseWriter, r *http.Request) {
+		exec.Command("git", "log", r.FormValue("ref")).Run() // want "potential command injection"
+	})
+
+	http.HandleFunc("/program", func(w http.ResponseWriter, r *http.Request) {
+		exec.Command(r.FormValue("program")).Run() // want "potential command injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		exec.Command("sh", "-c", "uptime").Run()
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
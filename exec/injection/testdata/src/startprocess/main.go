@@ -0,0 +1,22 @@
+package main
+
+import (
+	"net/http"
+	"os"
+)
+
+func main() {
+	http.HandleFunc("/name", func(w http.ResponseWriter, r *http.Request) {
+		os.StartProcess(r.FormValue("program"), nil, &os.ProcAttr{}) // want "potential command injection"
+	})
+
+	http.HandleFunc("/argv", func(w http.ResponseWriter, r *http.Request) {
+		os.StartProcess("/usr/bin/git", []string{"git", "log", r.FormValue("ref")}, &os.ProcAttr{}) // want "potential command injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		os.StartProcess("/usr/bin/uptime", []string{"uptime"}, &os.ProcAttr{})
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
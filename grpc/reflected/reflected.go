@@ -0,0 +1,149 @@
+package reflected
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/types"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/callgraphutil"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/callgraph"
+)
+
+// peerControlledValues are the sources of data sent by a peer, such as the
+// protobuf messages received from a gRPC stream.
+var peerControlledValues = taint.NewSources(
+	taint.ProtoMessageSource,
+)
+
+// egressFunctions are the functions which send data to peers.
+var egressFunctions = taint.NewSinks(
+	// Note: at this time, they *must* be a function or method.
+	"(google.golang.org/grpc.ServerStream).SendMsg",
+)
+
+// Rule is the taint rule for reflected data, where data received from one
+// peer is sent to (possibly other) peers, which can also be run directly
+// using taint.Run alongside other rules.
+//
+// The Send methods of the streams generated for each service are included
+// as sinks by the Analyzer, since their names depend on the service.
+var Rule = taint.NewRule(
+	"reflected",
+	"potential reflected data",
+	peerControlledValues,
+	egressFunctions,
+	nil,
+)
+
+// Analyzer finds potential reflected data issues.
+var Analyzer = &analysis.Analyzer{
+	Name:     "reflected",
+	Doc:      "finds potential reflected data sent to gRPC peers",
+	Run:      run,
+	Requires: []*analysis.Analyzer{buildssa.Analyzer},
+}
+
+// extraSources, extraSinks, and extraSanitizers are set using the analyzer's flags.
+var (
+	extraSources    taint.Sources
+	extraSinks      taint.Sinks
+	extraSanitizers taint.Sanitizers
+)
+
+func init() {
+	taint.RegisterFlags(&Analyzer.Flags, &extraSources, &extraSinks, &extraSanitizers)
+}
+
+// serverStream returns the google.golang.org/grpc.ServerStream interface
+// imported by the given package, or nil if it isn't imported.
+func serverStream(pkg *types.Package) *types.Interface {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() != "google.golang.org/grpc" {
+			continue
+		}
+		obj := imp.Scope().Lookup("ServerStream")
+		if obj == nil {
+			return nil
+		}
+		iface, _ := obj.Type().Underlying().(*types.Interface)
+		return iface
+	}
+	return nil
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	// Require the gRPC package is imported in the program being
+	// analyzed before running the analysis.
+	//
+	// This prevents wasting time analyzing programs without gRPC streams.
+	if len(extraSinks) == 0 && !taint.Imports(pass.Pkg, "google.golang.org/grpc") {
+		return nil, nil
+	}
+
+	// Get the built SSA IR.
+	buildSSA := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+
+	// Identify the main function from the package's SSA IR.
+	mainFn := buildSSA.Pkg.Func("main")
+	if mainFn == nil {
+		return nil, nil
+	}
+
+	// Construct a callgraph, using the main function as the root,
+	// constructed of all other functions. This returns a callgraph
+	// we can use to identify directed paths to egress functions.
+	cg, err := callgraphutil.NewGraph(mainFn, buildSSA.SrcFuncs...)
+	if err != nil {
+		// Functions which panicked while being added to the callgraph
+		// are skipped, continuing with the rest of the program.
+		var panicErr *callgraphutil.PanicError
+		if !errors.As(err, &panicErr) {
+			return nil, fmt.Errorf("failed to create new callgraph: %w", err)
+		}
+	}
+
+	// Paths which panicked while being checked are skipped.
+	results, _ := Run(cg)
+
+	for _, result := range results {
+		pass.Reportf(result.SinkValue.Pos(), "%s", result.Message)
+	}
+
+	return nil, nil
+}
+
+// Run performs the analyzer's reflected data check on the given callgraph.
+func Run(cg *callgraph.Graph) (taint.Results, error) {
+	return RunContext(context.Background(), cg)
+}
+
+// RunContext is like Run, but stops checking once the given context is
+// canceled, returning the context's error.
+func RunContext(ctx context.Context, cg *callgraph.Graph) (taint.Results, error) {
+	// Include the Send methods of the generated streams, which are the
+	// types implementing grpc.ServerStream, e.g. (*chatConnectServer).Send.
+	sinks := Rule.Sinks().Union(extraSinks)
+	if pkg := cg.Root.Func.Pkg; pkg != nil {
+		if iface := serverStream(pkg.Pkg); iface != nil {
+			sinks = sinks.Union(taint.SinkByInterfaceMethod(cg, iface, "Send"))
+		}
+	}
+
+	// Run the reflected data rule for peer controlled values (sources)
+	// ending up in egress functions (sinks), including any additional
+	// sources and sinks given using flags.
+	rule := taint.NewRule(
+		Rule.Name(),
+		Rule.Message(),
+		Rule.Sources().Union(extraSources),
+		sinks,
+		Rule.Sanitizers().Union(extraSanitizers),
+	)
+
+	return taint.RunContext(ctx, cg, rule)
+}
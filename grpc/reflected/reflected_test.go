@@ -0,0 +1,13 @@
+package reflected
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+var testdata = analysistest.TestData()
+
+func TestA(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "a")
+}
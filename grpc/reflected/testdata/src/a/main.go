@@ -0,0 +1,84 @@
+package main
+
+import (
+	"google.golang.org/grpc"
+)
+
+// ChatRequest and ChatResponse are similar to messages generated by
+// protoc-gen-go, which implement proto.Message using the generated
+// ProtoMessage method.
+type ChatRequest struct {
+	Room    string
+	Message string
+}
+
+func (*ChatRequest) ProtoMessage()    {}
+func (*ChatRequest) Reset()           {}
+func (x *ChatRequest) String() string { return x.Message }
+
+type ChatResponse struct {
+	Room    string
+	Message string
+}
+
+func (*ChatResponse) ProtoMessage()    {}
+func (*ChatResponse) Reset()           {}
+func (x *ChatResponse) String() string { return x.Message }
+
+// Chat_ConnectServer and chatConnectServer are similar to the streams
+// generated by protoc-gen-go-grpc for a bidirectional streaming method.
+type Chat_ConnectServer interface {
+	Send(*ChatResponse) error
+	Recv() (*ChatRequest, error)
+	grpc.ServerStream
+}
+
+type chatConnectServer struct {
+	grpc.ServerStream
+}
+
+func (x *chatConnectServer) Send(m *ChatResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *chatConnectServer) Recv() (*ChatRequest, error) {
+	m := new(ChatRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type server struct {
+	peers []Chat_ConnectServer
+}
+
+// Connect relays each message received from one peer to the others.
+func (s *server) Connect(stream Chat_ConnectServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		for _, peer := range s.peers {
+			peer.Send(&ChatResponse{Room: "lobby", Message: req.Message}) // want "potential reflected data"
+		}
+	}
+}
+
+// Status only sends constant data back to the peer.
+func (s *server) Status(stream Chat_ConnectServer) error {
+	if _, err := stream.Recv(); err != nil {
+		return err
+	}
+	return stream.Send(&ChatResponse{Room: "lobby", Message: "ok"})
+}
+
+func main() {
+	srv := &server{}
+
+	// In a real program, the gRPC server calls these methods with the
+	// streams it accepts, which are entirely user controlled.
+	srv.Connect(&chatConnectServer{})
+	srv.Status(&chatConnectServer{})
+}
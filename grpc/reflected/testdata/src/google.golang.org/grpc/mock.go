@@ -0,0 +1,10 @@
+package grpc
+
+import "context"
+
+// ServerStream is mocked from https://github.com/grpc/grpc-go/blob/v1.59.0/stream.go#L1473
+type ServerStream interface {
+	Context() context.Context
+	SendMsg(m any) error
+	RecvMsg(m any) error
+}
@@ -0,0 +1,124 @@
+package taint
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// LibraryEntryPoints returns the entry points of the given packages when they
+// are analyzed as a library, without a main function: each exported function,
+// and each exported method of an exported type. It also returns the string
+// parameters of each entry point, which are controlled by the library's
+// callers, so they should be treated as sources (see Options.Parameters).
+//
+// Parameters which are known not to be controlled by callers can be excluded
+// with a "//taint:trusted" directive in the function's doc comment, naming
+// the trusted parameters. This requires the packages to be parsed with
+// comments (parser.ParseComments).
+//
+//	// FindTable returns the rows of the given table.
+//	//
+//	//taint:trusted table
+//	func FindTable(db *sql.DB, table string) (*sql.Rows, error)
+//
+// The entry points can be used as the roots of a callgraph, such as with
+// callgraphutil.NewMultiRootGraph.
+func LibraryEntryPoints(pkgs []*ssa.Package) ([]*ssa.Function, []*ssa.Parameter) {
+	var (
+		entries []*ssa.Function
+		params  []*ssa.Parameter
+	)
+
+	add := func(fn *ssa.Function) {
+		if fn == nil || len(fn.Blocks) == 0 {
+			return
+		}
+		entries = append(entries, fn)
+		trusted := trustedParams(fn)
+		for _, param := range fn.Params {
+			// Skip the receiver, which is created by the library itself.
+			if fn.Signature.Recv() != nil && param == fn.Params[0] {
+				continue
+			}
+			if _, ok := trusted[param.Name()]; ok {
+				continue
+			}
+			if basic, ok := param.Type().Underlying().(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+				params = append(params, param)
+			}
+		}
+	}
+
+	for _, pkg := range pkgs {
+		if pkg == nil {
+			continue
+		}
+		for _, member := range pkg.Members {
+			if member.Object() == nil || !member.Object().Exported() {
+				continue
+			}
+			switch member := member.(type) {
+			case *ssa.Function:
+				add(member)
+			case *ssa.Type:
+				named, ok := member.Type().(*types.Named)
+				if !ok {
+					continue
+				}
+				for i := 0; i < named.NumMethods(); i++ {
+					method := named.Method(i)
+					if !method.Exported() {
+						continue
+					}
+					recv := method.Type().(*types.Signature).Recv().Type()
+					sel := pkg.Prog.MethodSets.MethodSet(recv).Lookup(method.Pkg(), method.Name())
+					if sel == nil {
+						continue
+					}
+					add(pkg.Prog.MethodValue(sel))
+				}
+			}
+		}
+	}
+
+	// Package members are a map, so sort the entry points to keep the
+	// callgraph (and its results) deterministic.
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].String() < entries[j].String()
+	})
+
+	return entries, params
+}
+
+// trustedDirective is the comment directive naming the trusted parameters
+// of a function, which aren't controlled by the function's callers.
+const trustedDirective = "//taint:trusted"
+
+// trustedParams returns the names of the parameters of the given function
+// listed by "//taint:trusted" directives in its doc comment.
+func trustedParams(fn *ssa.Function) map[string]struct{} {
+	decl, ok := fn.Syntax().(*ast.FuncDecl)
+	if !ok || decl.Doc == nil {
+		return nil
+	}
+
+	trusted := map[string]struct{}{}
+	for _, comment := range decl.Doc.List {
+		// Directives are read from the raw comments, since they are
+		// omitted from the comment group's text.
+		names, ok := strings.CutPrefix(comment.Text, trustedDirective)
+		if !ok || (names != "" && names[0] != ' ' && names[0] != '\t') {
+			continue
+		}
+		for _, name := range strings.FieldsFunc(names, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ','
+		}) {
+			trusted[name] = struct{}{}
+		}
+	}
+	return trusted
+}
@@ -0,0 +1,87 @@
+package taint_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/callgraphutil"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+func TestLibraryEntryPoints(t *testing.T) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedDeps |
+			packages.NeedFiles |
+			packages.NeedModule |
+			packages.NeedTypes |
+			packages.NeedImports |
+			packages.NeedSyntax |
+			packages.NeedTypesInfo,
+		Context: context.Background(),
+		Env:     os.Environ(),
+		Dir:     filepath.Join("testdata", "library"),
+	}, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prog, ssaPkgs := ssautil.Packages(pkgs, ssa.InstantiateGenerics)
+
+	prog.Build()
+
+	entries, params := taint.LibraryEntryPoints(ssaPkgs)
+
+	// The unexported findAdmin function isn't an entry point.
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entry points, got %v", entries)
+	}
+
+	// The name parameter of FindUser, and the id parameter of Delete, but
+	// not the table parameter of FindTable, which is trusted.
+	if len(params) != 2 {
+		t.Fatalf("expected 2 parameters, got %v", params)
+	}
+	for _, param := range params {
+		if param.Name() == "table" {
+			t.Fatalf("expected the trusted table parameter to be excluded, got %v", params)
+		}
+	}
+
+	var srcFns []*ssa.Function
+	for fn := range ssautil.AllFunctions(prog) {
+		if fn.Pkg == ssaPkgs[0] {
+			srcFns = append(srcFns, fn)
+		}
+	}
+
+	cg, err := callgraphutil.NewMultiRootGraph(entries, callgraphutil.GraphOptions{}, srcFns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := taint.CheckWithOptions(cg, taint.NewSources("*net/http.Request"), taint.NewSinks("(*database/sql.DB).Query", "(*database/sql.DB).Exec"), taint.Options{
+		Parameters: params,
+	})
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %v", len(results), results)
+	}
+
+	for _, result := range results {
+		if _, ok := result.SourceValue.(*ssa.Parameter); !ok {
+			t.Errorf("expected a parameter source, got %v", result.SourceValue)
+		}
+	}
+
+	// Without the parameters as sources, nothing is found.
+	results = taint.Check(cg, taint.NewSources("*net/http.Request"), taint.NewSinks("(*database/sql.DB).Query", "(*database/sql.DB).Exec"))
+	if len(results) != 0 {
+		t.Fatalf("expected no results without parameter sources, got %d", len(results))
+	}
+}
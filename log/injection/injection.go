@@ -1,14 +1,17 @@
 package injection
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/picatz/taint"
 	"github.com/picatz/taint/callgraphutil"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/ssa"
 )
 
 var userControlledValues = taint.NewSources(
@@ -71,17 +74,97 @@ var injectableLogFunctions = taint.NewSinks(
 	"(*log/slog.Record).Add",
 	"(*log/slog.Record).AddAttrs",
 
+	// k8s.io/klog
+	// https://pkg.go.dev/k8s.io/klog/v2
+	"k8s.io/klog/v2.Info",
+	"k8s.io/klog/v2.Infof",
+	"k8s.io/klog/v2.Infoln",
+	"k8s.io/klog/v2.Warning",
+	"k8s.io/klog/v2.Warningf",
+	"k8s.io/klog/v2.Warningln",
+	"k8s.io/klog/v2.Error",
+	"k8s.io/klog/v2.Errorf",
+	"k8s.io/klog/v2.Errorln",
+
+	// github.com/golang/glog
+	// https://pkg.go.dev/github.com/golang/glog
+	"github.com/golang/glog.Info",
+	"github.com/golang/glog.Infof",
+	"github.com/golang/glog.Infoln",
+	"github.com/golang/glog.Warning",
+	"github.com/golang/glog.Warningf",
+	"github.com/golang/glog.Warningln",
+	"github.com/golang/glog.Error",
+	"github.com/golang/glog.Errorf",
+	"github.com/golang/glog.Errorln",
+
+	// github.com/apex/log
+	// https://pkg.go.dev/github.com/apex/log
+	//
+	// Tainted fields, e.g. log.WithField("user", input).Info("login"),
+	// are found through the *Entry receiver of the logging method.
+	"github.com/apex/log.Debug",
+	"github.com/apex/log.Debugf",
+	"github.com/apex/log.Info",
+	"github.com/apex/log.Infof",
+	"github.com/apex/log.Warn",
+	"github.com/apex/log.Warnf",
+	"github.com/apex/log.Error",
+	"github.com/apex/log.Errorf",
+	"(*github.com/apex/log.Entry).Debug",
+	"(*github.com/apex/log.Entry).Debugf",
+	"(*github.com/apex/log.Entry).Info",
+	"(*github.com/apex/log.Entry).Infof",
+	"(*github.com/apex/log.Entry).Warn",
+	"(*github.com/apex/log.Entry).Warnf",
+	"(*github.com/apex/log.Entry).Error",
+	"(*github.com/apex/log.Entry).Errorf",
+
+	// github.com/go-kit/log
+	// https://pkg.go.dev/github.com/go-kit/log
+	//
+	// Only the values of the alternating key/value pairs are
+	// considered sinks, see keyvalsSink.
+	"(github.com/go-kit/log.Logger).Log",
+	"github.com/go-kit/log.With",
+	"github.com/go-kit/log.WithPrefix",
+	"github.com/go-kit/log.WithSuffix",
+
 	// TODO: consider adding the following logger packages,
 	//       and the ability to configure this list generically.
 	//
 	// https://pkg.go.dev/golang.org/x/exp/slog
-	// https://pkg.go.dev/github.com/golang/glog
 	// https://pkg.go.dev/github.com/hashicorp/go-hclog
 	// https://pkg.go.dev/github.com/sirupsen/logrus
 	// https://pkg.go.dev/go.uber.org/zap
 	// ...
 )
 
+// sanitizers make user controlled values safe to log, either by quoting
+// them, which escapes newlines (e.g. strconv.Quote), or by converting
+// them into types which can't contain newlines (e.g. strconv.Atoi).
+var sanitizers = taint.NewSanitizers(
+	"strconv.Quote",
+	"strconv.QuoteToASCII",
+	"strconv.Atoi",
+	"strconv.ParseBool",
+	"strconv.ParseFloat",
+	"strconv.ParseInt",
+	"strconv.ParseUint",
+	"net/url.QueryEscape",
+	"net/url.PathEscape",
+)
+
+// Rule is the taint rule for log injection, which can also be run
+// directly using taint.Run alongside other rules.
+var Rule = taint.NewRule(
+	"logi",
+	"potential log injection",
+	userControlledValues,
+	injectableLogFunctions,
+	sanitizers,
+)
+
 // Analyzer finds potential log injection issues to demonstrate
 // the github.com/picatz/taint package.
 var Analyzer = &analysis.Analyzer{
@@ -91,21 +174,61 @@ var Analyzer = &analysis.Analyzer{
 	Requires: []*analysis.Analyzer{buildssa.Analyzer},
 }
 
-// imports returns true if the package imports any of the given packages.
-func imports(pass *analysis.Pass, pkgs ...string) bool {
-	var imported bool
-	for _, imp := range pass.Pkg.Imports() {
-		for _, pkg := range pkgs {
-			if strings.HasSuffix(imp.Path(), pkg) {
-				imported = true
-				break
-			}
+// extraSources, extraSinks, and extraSanitizers are set using the analyzer's flags.
+var (
+	extraSources    taint.Sources
+	extraSinks      taint.Sinks
+	extraSanitizers taint.Sanitizers
+)
+
+func init() {
+	taint.RegisterFlags(&Analyzer.Flags, &extraSources, &extraSinks, &extraSanitizers)
+}
+
+// keyvalsFunctions are the sinks which take alternating key/value pairs
+// as variadic arguments, where only the values are considered sinks.
+var keyvalsFunctions = map[string]struct{}{
+	"(github.com/go-kit/log.Logger).Log": {},
+	"github.com/go-kit/log.With":         {},
+	"github.com/go-kit/log.WithPrefix":   {},
+	"github.com/go-kit/log.WithSuffix":   {},
+}
+
+// keyvalsSink returns false if the given call takes alternating key/value
+// pairs, and none of the value positions are non-constant, meaning only
+// the keys could be tainted. All other calls return true.
+func keyvalsSink(call *ssa.CallCommon) bool {
+	var name string
+	if call.IsInvoke() {
+		name = fmt.Sprintf("(%s).%s", call.Value.Type(), call.Method.Name())
+	} else {
+		name = call.Value.String()
+	}
+
+	if _, ok := keyvalsFunctions[name]; !ok || len(call.Args) == 0 {
+		return true
+	}
+
+	keyvals := taint.VariadicArgs(call.Args[len(call.Args)-1])
+	if len(keyvals) == 0 {
+		// The keyvals were not built at the call site (e.g. keyvals...),
+		// so we can't tell which positions are tainted.
+		return true
+	}
+
+	for i, v := range keyvals {
+		if i%2 == 0 {
+			continue
+		}
+		if mi, ok := v.(*ssa.MakeInterface); ok {
+			v = mi.X
 		}
-		if imported {
-			break
+		if _, isConst := v.(*ssa.Const); !isConst {
+			return true
 		}
 	}
-	return imported
+
+	return false
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
@@ -113,7 +236,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	// program being analyzed before running the analysis.
 	//
 	// This prevents wasting time analyzing programs that don't log.
-	if !imports(pass, "log", "log/slog") {
+	if len(extraSinks) == 0 && !taint.Imports(pass.Pkg, "log", "log/slog", "k8s.io/klog/v2", "github.com/golang/glog", "github.com/apex/log", "github.com/go-kit/log") {
 		return nil, nil
 	}
 
@@ -131,20 +254,53 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	// we can use to identify directed paths to logging functions.
 	cg, err := callgraphutil.NewGraph(mainFn, buildSSA.SrcFuncs...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create new callgraph: %w", err)
+		// Functions which panicked while being added to the callgraph
+		// are skipped, continuing with the rest of the program.
+		var panicErr *callgraphutil.PanicError
+		if !errors.As(err, &panicErr) {
+			return nil, fmt.Errorf("failed to create new callgraph: %w", err)
+		}
 	}
 
-	// Run taint check for user controlled values (sources) ending
-	// up in injectable log functions (sinks).
-	results := taint.Check(cg, userControlledValues, injectableLogFunctions)
+	// Paths which panicked while being checked are skipped.
+	results, _ := Run(cg)
 
-	// For each result, check if a prepared statement is providing
-	// a mitigation for the user controlled value.
-	//
-	// TODO: ensure this makes sense for all the GORM usage?
 	for _, result := range results {
-		pass.Reportf(result.SinkValue.Pos(), "potential log injection")
+		pass.Reportf(result.SinkValue.Pos(), "%s", result.Message)
 	}
 
 	return nil, nil
 }
+
+// Run performs the analyzer's log injection check on the given callgraph.
+func Run(cg *callgraph.Graph) (taint.Results, error) {
+	return RunContext(context.Background(), cg)
+}
+
+// RunContext is like Run, but stops checking once the given context is
+// canceled, returning the context's error.
+func RunContext(ctx context.Context, cg *callgraph.Graph) (taint.Results, error) {
+	// Run the log injection rule for user controlled values (sources)
+	// ending up in injectable log functions (sinks),
+	// including any additional sources and sinks given using flags.
+	rule := taint.NewRule(
+		Rule.Name(),
+		Rule.Message(),
+		Rule.Sources().Union(extraSources),
+		Rule.Sinks().Union(extraSinks),
+		Rule.Sanitizers().Union(extraSanitizers),
+	)
+
+	ruleResults, err := taint.RunContext(ctx, cg, rule)
+
+	var results taint.Results
+	for _, result := range ruleResults {
+		// Skip tainted keys passed to key/value loggers, such as go-kit's
+		// logger.Log(key, "value"), only the values are sinks.
+		if !keyvalsSink(result.Path.Last().Site.Common()) {
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, err
+}
@@ -35,3 +35,35 @@ func TestF(t *testing.T) {
 func TestG(t *testing.T) {
 	analysistest.Run(t, testdata, Analyzer, "g")
 }
+
+func TestSlogMsg(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "slogmsg")
+}
+
+func TestKlog(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "klog")
+}
+
+func TestApex(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "apex")
+}
+
+func TestGoKit(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "gokit")
+}
+
+func TestH(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "h")
+}
+
+func TestSanitize(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "sanitize")
+}
+
+func TestErrorf(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "errorf")
+}
+
+func TestKVAppend(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "kvappend")
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/apex/log"
+)
+
+func main() {
+	http.HandleFunc("/field", func(w http.ResponseWriter, r *http.Request) {
+		log.WithField("user", r.URL.Query().Get("user")).Info("login") // want "potential log injection"
+	})
+
+	http.HandleFunc("/message", func(w http.ResponseWriter, r *http.Request) {
+		log.WithField("path", "/message").Info(r.URL.Query().Get("input")) // want "potential log injection"
+	})
+
+	http.HandleFunc("/infof", func(w http.ResponseWriter, r *http.Request) {
+		log.Infof("input: %s", r.URL.Query().Get("input")) // want "potential log injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		log.WithField("path", "/safe").Info("request received")
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type inputError struct {
+	input string
+}
+
+func (e *inputError) Error() string {
+	return "bad input " + e.input
+}
+
+func validate(input string) error {
+	if input == "" {
+		return errors.New("missing input")
+	}
+	return &inputError{input: input}
+}
+
+func main() {
+	http.HandleFunc("/errorf", func(w http.ResponseWriter, r *http.Request) {
+		err := fmt.Errorf("bad input %s", r.URL.Query().Get("input"))
+		log.Println(err) // want "potential log injection"
+	})
+
+	http.HandleFunc("/error-string", func(w http.ResponseWriter, r *http.Request) {
+		err := fmt.Errorf("bad input %s", r.URL.Query().Get("input"))
+		log.Printf("request failed: %s", err.Error()) // want "potential log injection"
+	})
+
+	http.HandleFunc("/wrapped", func(w http.ResponseWriter, r *http.Request) {
+		err := fmt.Errorf("validating request: %w", validate(r.URL.Query().Get("input")))
+		log.Println(err) // want "potential log injection"
+	})
+
+	http.HandleFunc("/as", func(w http.ResponseWriter, r *http.Request) {
+		var inputErr *inputError
+		if errors.As(validate(r.URL.Query().Get("input")), &inputErr) {
+			log.Println(inputErr.input) // want "potential log injection"
+		}
+	})
+
+	http.HandleFunc("/constant", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("input") == "" {
+			log.Println(errors.New("missing input"))
+		}
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
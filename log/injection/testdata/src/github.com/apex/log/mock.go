@@ -0,0 +1,38 @@
+package log
+
+// Fields is mocked from https://github.com/apex/log/blob/v1.9.0/entry.go#L13
+type Fields map[string]interface{}
+
+// Entry is mocked from https://github.com/apex/log/blob/v1.9.0/entry.go#L16
+type Entry struct {
+	Fields  Fields
+	Message string
+}
+
+// WithField is mocked from https://github.com/apex/log/blob/v1.9.0/pkg.go#L46
+func WithField(key string, value interface{}) *Entry {
+	return &Entry{Fields: Fields{key: value}}
+}
+
+// WithFields is mocked from https://github.com/apex/log/blob/v1.9.0/pkg.go#L41
+func WithFields(fields Fields) *Entry {
+	return &Entry{Fields: fields}
+}
+
+// Info is mocked from https://github.com/apex/log/blob/v1.9.0/pkg.go#L66
+func Info(msg string) {}
+
+// Infof is mocked from https://github.com/apex/log/blob/v1.9.0/pkg.go#L91
+func Infof(msg string, v ...interface{}) {}
+
+// Info is mocked from https://github.com/apex/log/blob/v1.9.0/entry.go#L91
+func (e *Entry) Info(msg string) {}
+
+// Infof is mocked from https://github.com/apex/log/blob/v1.9.0/entry.go#L116
+func (e *Entry) Infof(msg string, v ...interface{}) {}
+
+// Warn is mocked from https://github.com/apex/log/blob/v1.9.0/entry.go#L96
+func (e *Entry) Warn(msg string) {}
+
+// Error is mocked from https://github.com/apex/log/blob/v1.9.0/entry.go#L101
+func (e *Entry) Error(msg string) {}
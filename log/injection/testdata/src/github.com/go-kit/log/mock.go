@@ -0,0 +1,26 @@
+package log
+
+import "io"
+
+// Logger is mocked from https://github.com/go-kit/log/blob/v0.2.1/log.go#L7
+type Logger interface {
+	Log(keyvals ...interface{}) error
+}
+
+type logfmtLogger struct {
+	w io.Writer
+}
+
+func (l logfmtLogger) Log(keyvals ...interface{}) error {
+	return nil
+}
+
+// NewLogfmtLogger is mocked from https://github.com/go-kit/log/blob/v0.2.1/logfmt_logger.go#L35
+func NewLogfmtLogger(w io.Writer) Logger {
+	return &logfmtLogger{w: w}
+}
+
+// With is mocked from https://github.com/go-kit/log/blob/v0.2.1/log.go#L31
+func With(logger Logger, keyvals ...interface{}) Logger {
+	return logger
+}
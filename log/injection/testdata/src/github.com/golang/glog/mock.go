@@ -0,0 +1,7 @@
+package glog
+
+// Info is mocked from https://github.com/golang/glog/blob/v1.2.0/glog.go#L402
+func Info(args ...any) {}
+
+// Infof is mocked from https://github.com/golang/glog/blob/v1.2.0/glog.go#L432
+func Infof(format string, args ...any) {}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/go-kit/log"
+)
+
+func main() {
+	logger := log.NewLogfmtLogger(os.Stderr)
+
+	http.HandleFunc("/value", func(w http.ResponseWriter, r *http.Request) {
+		logger.Log("msg", "request received", "input", r.URL.Query().Get("input")) // want "potential log injection"
+	})
+
+	http.HandleFunc("/with", func(w http.ResponseWriter, r *http.Request) {
+		log.With(logger, "user", r.URL.Query().Get("user")).Log("msg", "request received") // want "potential log injection"
+	})
+
+	http.HandleFunc("/key", func(w http.ResponseWriter, r *http.Request) {
+		logger.Log(r.URL.Query().Get("key"), "request received")
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		logger.Log("msg", "request received", "path", "/safe")
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
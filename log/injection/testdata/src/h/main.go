@@ -0,0 +1,26 @@
+package main
+
+import (
+	"log"
+	"net/http"
+)
+
+func main() {
+	http.HandleFunc("/middle", func(w http.ResponseWriter, r *http.Request) {
+		log.Println("prefix", r.URL.Query().Get("input"), "suffix") // want "potential log injection"
+	})
+
+	http.HandleFunc("/last", func(w http.ResponseWriter, r *http.Request) {
+		log.Println("prefix", 1, true, r.Header.Get("User-Agent")) // want "potential log injection"
+	})
+
+	http.HandleFunc("/clean", func(w http.ResponseWriter, r *http.Request) {
+		input := r.URL.Query().Get("input")
+		if input == "" {
+			return
+		}
+		log.Println("prefix", "clean", "suffix")
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,13 @@
+package klog
+
+// Info is mocked from https://github.com/kubernetes/klog/blob/v2.110.1/klog.go#L1465
+func Info(args ...interface{}) {}
+
+// Infof is mocked from https://github.com/kubernetes/klog/blob/v2.110.1/klog.go#L1495
+func Infof(format string, args ...interface{}) {}
+
+// Warning is mocked from https://github.com/kubernetes/klog/blob/v2.110.1/klog.go#L1536
+func Warning(args ...interface{}) {}
+
+// Error is mocked from https://github.com/kubernetes/klog/blob/v2.110.1/klog.go#L1584
+func Error(args ...interface{}) {}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"k8s.io/klog/v2"
+)
+
+func main() {
+	http.HandleFunc("/klog/info", func(w http.ResponseWriter, r *http.Request) {
+		klog.Info(r.URL.Query().Get("input")) // want "potential log injection"
+	})
+
+	http.HandleFunc("/klog/infof", func(w http.ResponseWriter, r *http.Request) {
+		klog.Infof("input: %s", r.URL.Query().Get("input")) // want "potential log injection"
+	})
+
+	http.HandleFunc("/klog/warning", func(w http.ResponseWriter, r *http.Request) {
+		klog.Warning(r.URL.Query().Get("input")) // want "potential log injection"
+	})
+
+	http.HandleFunc("/klog/error", func(w http.ResponseWriter, r *http.Request) {
+		klog.Error(r.URL.Query().Get("input")) // want "potential log injection"
+	})
+
+	http.HandleFunc("/glog/info", func(w http.ResponseWriter, r *http.Request) {
+		glog.Info(r.URL.Query().Get("input")) // want "potential log injection"
+	})
+
+	http.HandleFunc("/glog/infof", func(w http.ResponseWriter, r *http.Request) {
+		glog.Infof("input: %s", r.URL.Query().Get("input")) // want "potential log injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		klog.Info("request received")
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+)
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	http.HandleFunc("/append", func(w http.ResponseWriter, r *http.Request) {
+		kv := []interface{}{"path", "/append"}
+		kv = append(kv, "user", r.URL.Query().Get("user"))
+		logger.Info("request received", kv...) // want "potential log injection"
+	})
+
+	http.HandleFunc("/append-later", func(w http.ResponseWriter, r *http.Request) {
+		var kv []interface{}
+		kv = append(kv, "path", "/append-later")
+		if user := r.URL.Query().Get("user"); user != "" {
+			kv = append(kv, "user", user)
+		}
+		logger.Info("request received", kv...) // want "potential log injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		var kv []interface{}
+		kv = append(kv, "path", "/safe")
+		logger.Info("request received", kv...)
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+)
+
+func main() {
+	http.HandleFunc("/quote", func(w http.ResponseWriter, r *http.Request) {
+		log.Println(strconv.Quote(r.URL.Query().Get("input")))
+	})
+
+	http.HandleFunc("/atoi", func(w http.ResponseWriter, r *http.Request) {
+		n, err := strconv.Atoi(r.URL.Query().Get("n"))
+		if err != nil {
+			return
+		}
+		log.Printf("n=%d", n)
+	})
+
+	http.HandleFunc("/unsanitized", func(w http.ResponseWriter, r *http.Request) {
+		log.Println(r.URL.Query().Get("input")) // want "potential log injection"
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+)
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		user := r.URL.Query().Get("user")
+
+		// The message itself is tainted, not just the attributes.
+		func() {
+			slog.Info(fmt.Sprintf("user %s logged in", user)) // want "potential log injection"
+		}()
+
+		func() {
+			logger.Warn("user " + user + " logged out") // want "potential log injection"
+		}()
+
+		func() {
+			logger.ErrorContext(context.Background(), fmt.Sprintf("user %s failed", user)) // want "potential log injection"
+		}()
+
+		// A constant message with tainted attributes is still flagged.
+		func() {
+			slog.Info("login", "user", user) // want "potential log injection"
+		}()
+
+		// A constant message without tainted data is fine.
+		func() {
+			slog.Info("healthy")
+		}()
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
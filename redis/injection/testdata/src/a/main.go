@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func main() {
+	rdb := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+
+	http.HandleFunc("/set", func(w http.ResponseWriter, r *http.Request) {
+		key, value := r.FormValue("key"), r.FormValue("value")
+		rdb.Do(context.Background(), "SET", key, value) // want "potential redis injection"
+	})
+
+	http.HandleFunc("/incr", func(w http.ResponseWriter, r *http.Request) {
+		rdb.Do(context.Background(), "INCRBY", "counter:"+r.URL.Query().Get("name"), 1) // want "potential redis injection"
+	})
+
+	http.HandleFunc("/eval", func(w http.ResponseWriter, r *http.Request) {
+		rdb.Eval(context.Background(), r.FormValue("script"), []string{"key"}) // want "potential redis injection"
+	})
+
+	http.HandleFunc("/get", func(w http.ResponseWriter, r *http.Request) {
+		rdb.Get(context.Background(), r.FormValue("key"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
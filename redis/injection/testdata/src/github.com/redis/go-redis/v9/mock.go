@@ -0,0 +1,34 @@
+package redis
+
+import "context"
+
+// Options is mocked from https://github.com/redis/go-redis/blob/v9.3.0/options.go#L31
+type Options struct {
+	Addr string
+}
+
+// Client is mocked from https://github.com/redis/go-redis/blob/v9.3.0/redis.go#L600
+type Client struct{}
+
+// Cmd is mocked from https://github.com/redis/go-redis/blob/v9.3.0/command.go#L299
+type Cmd struct{}
+
+// NewClient is mocked from https://github.com/redis/go-redis/blob/v9.3.0/redis.go#L609
+func NewClient(opt *Options) *Client {
+	return nil
+}
+
+// Do is mocked from https://github.com/redis/go-redis/blob/v9.3.0/redis.go#L406
+func (c *Client) Do(ctx context.Context, args ...interface{}) *Cmd {
+	return nil
+}
+
+// Eval is mocked from https://github.com/redis/go-redis/blob/v9.3.0/commands.go#L3283
+func (c *Client) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *Cmd {
+	return nil
+}
+
+// Get is mocked from https://github.com/redis/go-redis/blob/v9.3.0/string_commands.go#L59
+func (c *Client) Get(ctx context.Context, key string) *Cmd {
+	return nil
+}
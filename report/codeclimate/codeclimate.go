@@ -0,0 +1,111 @@
+// Package codeclimate writes taint check results as a Code Climate issue
+// JSON array, which is used by GitLab code quality reports.
+//
+// https://github.com/codeclimate/platform/blob/master/spec/analyzers/SPEC.md#data-types
+package codeclimate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/report"
+)
+
+// Issue is a Code Climate issue for a taint finding.
+//
+// The report must be a JSON array of issues to be understood by GitLab, so
+// the report.SchemaVersion is included in each issue instead of the root.
+type Issue struct {
+	SchemaVersion string `json:"schemaVersion"`
+
+	Type        string   `json:"type"`
+	CheckName   string   `json:"check_name"`
+	Description string   `json:"description"`
+	Categories  []string `json:"categories"`
+	Location    Location `json:"location"`
+	Severity    string   `json:"severity"`
+	Fingerprint string   `json:"fingerprint"`
+}
+
+// Location is the location of an issue, relative to the project's root.
+type Location struct {
+	Path  string `json:"path"`
+	Lines Lines  `json:"lines"`
+}
+
+// Lines are the lines of an issue's location.
+type Lines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// Write writes the given results to w as a Code Climate issue JSON array,
+// with paths relative to the given root directory of the project.
+func Write(w io.Writer, results taint.Results, root string) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(New(results, root)); err != nil {
+		return fmt.Errorf("failed to write code climate report: %w", err)
+	}
+
+	return nil
+}
+
+// New returns the Code Climate issues for the given results, with paths
+// relative to the given root directory of the project.
+func New(results taint.Results, root string) []Issue {
+	issues := make([]Issue, 0, len(results))
+
+	for _, result := range results {
+		pos := result.Position()
+
+		path := pos.Filename
+		if rel, err := filepath.Rel(root, path); err == nil {
+			path = rel
+		}
+		path = filepath.ToSlash(path)
+
+		checkName := result.Rule
+		if checkName == "" {
+			checkName = "taint"
+		}
+
+		description := result.Message
+		if description == "" {
+			description = fmt.Sprintf("%s reaches %s", result.SourceType, result.Path.Last().Callee.Func)
+		}
+
+		issues = append(issues, Issue{
+			SchemaVersion: report.SchemaVersion,
+			Type:          "issue",
+			CheckName:     checkName,
+			Description:   description,
+			Categories:    []string{"Security"},
+			Location: Location{
+				Path: path,
+				Lines: Lines{
+					Begin: pos.Line,
+					End:   pos.Line,
+				},
+			},
+			Severity:    "major",
+			Fingerprint: Fingerprint(checkName, path, pos.Line, pos.Column),
+		})
+	}
+
+	return issues
+}
+
+// Fingerprint returns a stable fingerprint for an issue found by the given
+// check (rule) at the given sink position, so the same finding can be
+// tracked across runs.
+func Fingerprint(checkName, path string, line, column int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%d:%d", checkName, path, line, column)))
+	return hex.EncodeToString(sum[:])
+}
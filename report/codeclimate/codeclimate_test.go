@@ -0,0 +1,96 @@
+package codeclimate_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/report"
+	"github.com/picatz/taint/report/codeclimate"
+)
+
+const src = `package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+var db *sql.DB
+
+func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		db.Query("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'")
+	})
+	http.ListenAndServe(":8080", nil)
+}
+`
+
+var rule = taint.NewRule(
+	"sqli",
+	"potential sql injection",
+	taint.NewSources("*net/http.Request"),
+	taint.NewSinks("(*database/sql.DB).Query"),
+	nil,
+)
+
+// analyze returns the Code Climate issues for the source, which is
+// analyzed in a new temporary directory each time.
+func analyze(t *testing.T) []codeclimate.Issue {
+	t.Helper()
+
+	results, err := taint.AnalyzeSource(src, rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %v", len(results), results)
+	}
+
+	root := filepath.Dir(results[0].Position().Filename)
+
+	var buf bytes.Buffer
+
+	err = codeclimate.Write(&buf, results, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var issues []codeclimate.Issue
+
+	err = json.Unmarshal(buf.Bytes(), &issues)
+	if err != nil {
+		t.Fatalf("failed to parse code climate report: %v\n%s", err, buf.String())
+	}
+
+	return issues
+}
+
+func TestWrite(t *testing.T) {
+	first, second := analyze(t), analyze(t)
+
+	if len(first) != 1 || len(second) != 1 {
+		t.Fatalf("expected 1 issue for each run, got %d and %d", len(first), len(second))
+	}
+
+	issue := first[0]
+
+	if issue.SchemaVersion != report.SchemaVersion {
+		t.Errorf("expected schema version %q, got %q", report.SchemaVersion, issue.SchemaVersion)
+	}
+
+	if issue.CheckName != "sqli" {
+		t.Errorf("expected check name %q, got %q", "sqli", issue.CheckName)
+	}
+
+	if issue.Location.Path != "main.go" || issue.Location.Lines.Begin != 12 {
+		t.Errorf("expected location main.go:12, got %s:%d", issue.Location.Path, issue.Location.Lines.Begin)
+	}
+
+	if issue.Fingerprint == "" || issue.Fingerprint != second[0].Fingerprint {
+		t.Errorf("expected deterministic fingerprints, got %q and %q", issue.Fingerprint, second[0].Fingerprint)
+	}
+}
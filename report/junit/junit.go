@@ -0,0 +1,152 @@
+// Package junit writes taint check results as JUnit XML, which is
+// understood by many CI systems to display test results.
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/report"
+)
+
+// TestSuites is the root element of a JUnit XML report.
+type TestSuites struct {
+	XMLName       xml.Name    `xml:"testsuites"`
+	SchemaVersion string      `xml:"schemaVersion,attr"`
+	Name          string      `xml:"name,attr"`
+	Tests         int         `xml:"tests,attr"`
+	Failures      int         `xml:"failures,attr"`
+	Suites        []TestSuite `xml:"testsuite"`
+}
+
+// TestSuite is a collection of test cases, one for each package.
+type TestSuite struct {
+	Name     string     `xml:"name,attr"`
+	Tests    int        `xml:"tests,attr"`
+	Failures int        `xml:"failures,attr"`
+	Cases    []TestCase `xml:"testcase"`
+}
+
+// TestCase is an analyzed function, which has a failure for
+// each taint finding within it.
+type TestCase struct {
+	Name      string    `xml:"name,attr"`
+	ClassName string    `xml:"classname,attr"`
+	File      string    `xml:"file,attr,omitempty"`
+	Failures  []Failure `xml:"failure"`
+}
+
+// Failure is an individual taint finding.
+type Failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Write writes the given results to w as a JUnit XML report, with a test
+// case for each function containing a sink call, and a failure for each
+// result within it.
+func Write(w io.Writer, results taint.Results) error {
+	report := New(results)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write junit report: %w", err)
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to write junit report: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// New returns a JUnit report for the given results, which can be
+// further modified before being encoded.
+func New(results taint.Results) *TestSuites {
+	var (
+		suites = map[string]*TestSuite{}
+		cases  = map[string]*TestCase{}
+	)
+
+	for _, result := range results {
+		fn := result.SinkValue.Parent()
+
+		// Synthetic functions (e.g. wrappers) don't have a package.
+		var pkg, name = "", fn.String()
+		if fn.Pkg != nil {
+			pkg, name = fn.Pkg.Pkg.Path(), fn.RelString(fn.Pkg.Pkg)
+		}
+
+		suite, ok := suites[pkg]
+		if !ok {
+			suite = &TestSuite{Name: pkg}
+			suites[pkg] = suite
+		}
+
+		tc, ok := cases[fn.String()]
+		if !ok {
+			tc = &TestCase{
+				Name:      name,
+				ClassName: pkg,
+				File:      result.Position().Filename,
+			}
+			cases[fn.String()] = tc
+		}
+
+		message := result.Message
+		if message == "" {
+			message = fmt.Sprintf("%s reaches %s", result.SourceType, result.Path.Last().Callee.Func)
+		}
+
+		kind := result.Rule
+		if kind == "" {
+			kind = "taint"
+		}
+
+		tc.Failures = append(tc.Failures, Failure{
+			Message: message,
+			Type:    kind,
+			Text:    fmt.Sprintf("%s\n%s", result.Position(), result.Path),
+		})
+	}
+
+	// Add the test cases to their suites, sorted by name
+	// so the output is stable.
+	names := make([]string, 0, len(cases))
+	for name := range cases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	root := &TestSuites{SchemaVersion: report.SchemaVersion, Name: "taint"}
+
+	for _, name := range names {
+		tc := cases[name]
+		suite := suites[tc.ClassName]
+		suite.Cases = append(suite.Cases, *tc)
+		suite.Tests++
+		suite.Failures += len(tc.Failures)
+	}
+
+	pkgs := make([]string, 0, len(suites))
+	for pkg := range suites {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+
+	for _, pkg := range pkgs {
+		suite := suites[pkg]
+		root.Suites = append(root.Suites, *suite)
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+	}
+
+	return root
+}
@@ -0,0 +1,106 @@
+package junit_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/report"
+	"github.com/picatz/taint/report/junit"
+)
+
+func TestWrite(t *testing.T) {
+	src := `package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+var db *sql.DB
+
+func search(w http.ResponseWriter, r *http.Request) {
+	db.Query("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'")
+}
+
+func lookup(w http.ResponseWriter, r *http.Request) {
+	db.Exec("DELETE FROM users WHERE id = " + r.FormValue("id"))
+}
+
+func main() {
+	http.HandleFunc("/search", search)
+	http.HandleFunc("/lookup", lookup)
+	http.ListenAndServe(":8080", nil)
+}
+`
+
+	results, err := taint.AnalyzeSource(src, taint.NewRule(
+		"sqli",
+		"potential sql injection",
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query", "(*database/sql.DB).Exec"),
+		nil,
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %v", len(results), results)
+	}
+
+	var buf bytes.Buffer
+
+	err = junit.Write(&buf, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var report junit.TestSuites
+
+	err = xml.Unmarshal(buf.Bytes(), &report)
+	if err != nil {
+		t.Fatalf("failed to parse junit report: %v\n%s", err, buf.String())
+	}
+
+	if report.Failures != len(results) {
+		t.Fatalf("expected %d failures, got %d:\n%s", len(results), report.Failures, buf.String())
+	}
+
+	var failures int
+	for _, suite := range report.Suites {
+		for _, tc := range suite.Cases {
+			for _, failure := range tc.Failures {
+				failures++
+				if failure.Message != "potential sql injection" {
+					t.Errorf("expected failure message %q, got %q", "potential sql injection", failure.Message)
+				}
+			}
+		}
+	}
+
+	if failures != len(results) {
+		t.Fatalf("expected %d failure elements, got %d:\n%s", len(results), failures, buf.String())
+	}
+}
+
+func TestWriteSchemaVersion(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := junit.Write(&buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var suites junit.TestSuites
+
+	err = xml.Unmarshal(buf.Bytes(), &suites)
+	if err != nil {
+		t.Fatalf("failed to parse junit report: %v\n%s", err, buf.String())
+	}
+
+	if suites.SchemaVersion != report.SchemaVersion {
+		t.Fatalf("expected schema version %q, got %q:\n%s", report.SchemaVersion, suites.SchemaVersion, buf.String())
+	}
+}
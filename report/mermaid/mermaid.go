@@ -0,0 +1,120 @@
+// Package mermaid writes taint check results as Mermaid flowcharts, which
+// can be embedded in markdown documents, such as pull requests, to show
+// the path of each finding.
+//
+// https://mermaid.js.org/syntax/flowchart.html
+package mermaid
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/picatz/taint"
+)
+
+// Write writes the given results to w as markdown, with a fenced Mermaid
+// flowchart for each result (see WriteGraph).
+func Write(w io.Writer, results taint.Results) error {
+	for i, result := range results {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return fmt.Errorf("failed to write mermaid report: %w", err)
+			}
+		}
+
+		if _, err := io.WriteString(w, "```mermaid\n"); err != nil {
+			return fmt.Errorf("failed to write mermaid report: %w", err)
+		}
+
+		if err := WriteGraph(w, result); err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(w, "```\n"); err != nil {
+			return fmt.Errorf("failed to write mermaid report: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// WriteGraph writes the path of the given result to w as a Mermaid "graph LR"
+// flowchart, with a node for each function in the path, from left to right,
+// and the source and sink highlighted. The source is connected to the node
+// of the function it was found in, using a dotted edge.
+//
+//	graph LR
+//	  n0["main.main"]
+//	  n1["main.search"]
+//	  n2["(*database/sql.DB).Query"]
+//	  n0 --> n1
+//	  n1 --> n2
+//	  source(["*net/http.Request"])
+//	  source -.-> n1
+//	  classDef source fill:#d4f4dd,stroke:#2e7d32
+//	  classDef sink fill:#fde0dc,stroke:#c62828
+//	  class source source
+//	  class n2 sink
+func WriteGraph(w io.Writer, result taint.Result) error {
+	var b strings.Builder
+
+	b.WriteString("graph LR\n")
+
+	if len(result.Path) > 0 {
+		// Declare each node once, even if the path visits it again.
+		declared := map[int]bool{}
+		declare := func(id int, label string) {
+			if declared[id] {
+				return
+			}
+			declared[id] = true
+			fmt.Fprintf(&b, "  n%d[\"%s\"]\n", id, escape(label))
+		}
+
+		declare(result.Path[0].Caller.ID, result.Path[0].Caller.Func.String())
+		for _, edge := range result.Path {
+			declare(edge.Callee.ID, edge.Callee.Func.String())
+		}
+
+		for _, edge := range result.Path {
+			fmt.Fprintf(&b, "  n%d --> n%d\n", edge.Caller.ID, edge.Callee.ID)
+		}
+
+		// Connect the source to the function it was found in, if it's
+		// part of the path, otherwise the start of the path.
+		sourceNode := result.Path[0].Caller.ID
+		if result.SourceValue != nil {
+			for _, edge := range result.Path {
+				if edge.Callee.Func == result.SourceValue.Parent() {
+					sourceNode = edge.Callee.ID
+					break
+				}
+			}
+		}
+
+		source := result.SourceName
+		if source == "" {
+			source = result.SourceType
+		}
+
+		fmt.Fprintf(&b, "  source([\"%s\"])\n", escape(source))
+		fmt.Fprintf(&b, "  source -.-> n%d\n", sourceNode)
+		b.WriteString("  classDef source fill:#d4f4dd,stroke:#2e7d32\n")
+		b.WriteString("  classDef sink fill:#fde0dc,stroke:#c62828\n")
+		b.WriteString("  class source source\n")
+		fmt.Fprintf(&b, "  class n%d sink\n", result.Path.Last().Callee.ID)
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write mermaid graph: %w", err)
+	}
+
+	return nil
+}
+
+// escape escapes the given label for use within a quoted Mermaid node label,
+// using Mermaid's entity codes for characters which would end the label.
+func escape(label string) string {
+	return strings.NewReplacer(`"`, "#quot;").Replace(label)
+}
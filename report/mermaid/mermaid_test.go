@@ -0,0 +1,79 @@
+package mermaid_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/report/mermaid"
+)
+
+func TestWrite(t *testing.T) {
+	src := `package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+var db *sql.DB
+
+func search(w http.ResponseWriter, r *http.Request) {
+	db.Query("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'")
+}
+
+func main() {
+	http.HandleFunc("/search", search)
+	http.ListenAndServe(":8080", nil)
+}
+`
+
+	results, err := taint.AnalyzeSource(src, taint.NewRule(
+		"sqli",
+		"potential sql injection",
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+		nil,
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %v", len(results), results)
+	}
+
+	var buf bytes.Buffer
+
+	err = mermaid.Write(&buf, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+
+	path := results[0].Path
+	search := path[len(path)-2].Callee
+	query := path.Last().Callee
+
+	for _, want := range []string{
+		"```mermaid\ngraph LR\n",
+		fmt.Sprintf("  n%d[\"main.search\"]\n", search.ID),
+		fmt.Sprintf("  n%d[\"(*database/sql.DB).Query\"]\n", query.ID),
+		fmt.Sprintf("  n%d --> n%d\n", search.ID, query.ID),
+		"  source([\"*net/http.Request\"])\n",
+		fmt.Sprintf("  source -.-> n%d\n", search.ID),
+		"  class source source\n",
+		fmt.Sprintf("  class n%d sink\n", query.ID),
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	if !strings.HasSuffix(out, "```\n") {
+		t.Errorf("expected the flowchart to be fenced, got:\n%s", out)
+	}
+}
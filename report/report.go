@@ -0,0 +1,9 @@
+// Package report contains the machine-readable output formats for taint
+// check results, such as Code Climate and JUnit, in its subpackages.
+package report
+
+// SchemaVersion is the version of the machine-readable report formats,
+// which is included in each report, so consumers can detect changes
+// to them. It is incremented when a field is removed, renamed, or
+// changes meaning, but not when a field is added.
+const SchemaVersion = "1"
@@ -0,0 +1,97 @@
+package taint
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/tools/go/callgraph"
+)
+
+// Rule describes a kind of taint check, such as SQL injection, in
+// terms of its sources, sinks, and sanitizers. This allows custom
+// checks to be defined and run alongside the built-in analyzers,
+// without needing to fork them.
+type Rule interface {
+	// Name is a short, unique name for the rule (e.g. "sqli").
+	Name() string
+	// Sources are the user controlled values for the rule.
+	Sources() Sources
+	// Sinks are the dangerous functions for the rule.
+	Sinks() Sinks
+	// Sanitizers are the functions that make tainted values safe
+	// to use in the rule's sinks, which may be empty.
+	Sanitizers() Sanitizers
+	// Message describes a result of the rule, which is typically
+	// used as the message of a reported diagnostic.
+	Message() string
+}
+
+// NewRule returns a new Rule using the given name, message, sources,
+// sinks, and sanitizers.
+func NewRule(name, message string, sources Sources, sinks Sinks, sanitizers Sanitizers) Rule {
+	return &rule{
+		name:       name,
+		message:    message,
+		sources:    sources,
+		sinks:      sinks,
+		sanitizers: sanitizers,
+	}
+}
+
+// rule is the default implementation of Rule returned by NewRule.
+type rule struct {
+	name       string
+	message    string
+	sources    Sources
+	sinks      Sinks
+	sanitizers Sanitizers
+}
+
+func (r *rule) Name() string           { return r.name }
+func (r *rule) Message() string        { return r.message }
+func (r *rule) Sources() Sources       { return r.sources }
+func (r *rule) Sinks() Sinks           { return r.sinks }
+func (r *rule) Sanitizers() Sanitizers { return r.sanitizers }
+
+// Run performs a taint check on the callgraph for each of the given rules,
+// returning the results of all of them. Each result is tagged with the name
+// and message of the rule that produced it. Paths which panicked while being
+// checked are skipped, use RunContext to have them returned.
+func Run(cg *callgraph.Graph, rules ...Rule) Results {
+	results, _ := RunContext(context.Background(), cg, rules...)
+	return results
+}
+
+// RunContext is like Run, but stops checking once the given context is
+// canceled. Paths which panicked while being checked are skipped, and
+// returned as *callgraphutil.PanicError errors, along with the results.
+func RunContext(ctx context.Context, cg *callgraph.Graph, rules ...Rule) (Results, error) {
+	var (
+		results = Results{}
+		errs    []error
+	)
+
+	for _, rule := range rules {
+		ruleResults, err := CheckContextWithOptions(ctx, cg, rule.Sources(), rule.Sinks(), Options{
+			Sanitizers: rule.Sanitizers(),
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		for _, result := range ruleResults {
+			result.Rule = rule.Name()
+			result.Message = rule.Message()
+
+			results = append(results, result)
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	SortResults(results)
+
+	return results, errors.Join(errs...)
+}
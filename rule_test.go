@@ -0,0 +1,79 @@
+package taint_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/callgraphutil"
+	"golang.org/x/tools/go/callgraph"
+)
+
+func TestRun(t *testing.T) {
+	cg := loadCallGraph(t, "rule")
+
+	shell := taint.NewRule(
+		"shell",
+		"potential shell injection",
+		taint.NewSources("(*github.com/picatz/taint/testdata/rule.Request).Param"),
+		taint.NewSinks("github.com/picatz/taint/testdata/rule.Shell"),
+		taint.NewSanitizers("strconv.Quote"),
+	)
+
+	results := taint.Run(cg, shell)
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %v", len(results), results)
+	}
+
+	result := results[0]
+
+	if result.Rule != "shell" {
+		t.Errorf("expected result rule %q, got %q", "shell", result.Rule)
+	}
+
+	if result.Message != "potential shell injection" {
+		t.Errorf("expected result message %q, got %q", "potential shell injection", result.Message)
+	}
+
+	if caller := result.Path.Last().Caller.Func.Name(); caller != "greet" {
+		t.Errorf("expected result from %q, got %q", "greet", caller)
+	}
+}
+
+func TestRunContextPanic(t *testing.T) {
+	cg := loadCallGraph(t, "sqli")
+
+	// Add a malformed edge without a call site to the sink, which panics
+	// when the path is checked, but must not stop the other paths.
+	var query *callgraph.Node
+	for fn, node := range cg.Nodes {
+		if fn.String() == "(*database/sql.DB).Query" {
+			query = node
+		}
+	}
+	if query == nil {
+		t.Fatal("query node not found")
+	}
+	callgraph.AddEdge(cg.Root, nil, query)
+
+	sqli := taint.NewRule(
+		"sqli",
+		"potential sql injection",
+		taint.NewSources("*net/http.Request"),
+		taint.NewSinks("(*database/sql.DB).Query"),
+		taint.NewSanitizers("strconv.Atoi"),
+	)
+
+	results, err := taint.RunContext(context.Background(), cg, sqli)
+
+	var panicErr *callgraphutil.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected a panic error, got %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %v", len(results), results)
+	}
+}
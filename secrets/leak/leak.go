@@ -0,0 +1,154 @@
+package leak
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/callgraphutil"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/callgraph"
+)
+
+// sensitiveValues are the sources of secrets, such as environment variables
+// with names like API_SECRET, which shouldn't leave the program.
+var sensitiveValues = taint.NewSources(
+	`os.Getenv("*SECRET*")`,
+	`os.Getenv("*TOKEN*")`,
+	`os.Getenv("*PASSWORD*")`,
+	`os.Getenv("*API_KEY*")`,
+	`os.Getenv("*PRIVATE_KEY*")`,
+	`os.LookupEnv("*SECRET*")`,
+	`os.LookupEnv("*TOKEN*")`,
+	`os.LookupEnv("*PASSWORD*")`,
+	`os.LookupEnv("*API_KEY*")`,
+	`os.LookupEnv("*PRIVATE_KEY*")`,
+)
+
+// egressFunctions are the functions which send data out of the program to
+// places that may be seen by others, such as logs and HTTP responses.
+var egressFunctions = taint.NewSinks(
+	// Note: at this time, they *must* be a function or method.
+	"log.Print",
+	"log.Printf",
+	"log.Println",
+	"log.Fatal",
+	"log.Fatalf",
+	"log.Fatalln",
+	"log.Panic",
+	"log.Panicf",
+	"log.Panicln",
+	"(*log.Logger).Print",
+	"(*log.Logger).Printf",
+	"(*log.Logger).Println",
+	"log/slog.Debug",
+	"log/slog.Info",
+	"log/slog.Warn",
+	"log/slog.Error",
+	"(*log/slog.Logger).Debug",
+	"(*log/slog.Logger).Info",
+	"(*log/slog.Logger).Warn",
+	"(*log/slog.Logger).Error",
+	"fmt.Print",
+	"fmt.Printf",
+	"fmt.Println",
+	"(net/http.ResponseWriter).Write",
+)
+
+// Rule is the taint rule for secrets leaking out of the program, which can
+// also be run directly using taint.Run alongside other rules.
+//
+// Unlike the injection rules, the sources are sensitive values, instead of
+// user controlled values, and the sinks are places they may be seen.
+var Rule = taint.NewRule(
+	"secretleak",
+	"potential secret leak",
+	sensitiveValues,
+	egressFunctions,
+	nil,
+)
+
+// Analyzer finds potential secret leaks.
+var Analyzer = &analysis.Analyzer{
+	Name:     "secretleak",
+	Doc:      "finds potential secret leaks",
+	Run:      run,
+	Requires: []*analysis.Analyzer{buildssa.Analyzer},
+}
+
+// extraSources, extraSinks, and extraSanitizers are set using the analyzer's flags.
+var (
+	extraSources    taint.Sources
+	extraSinks      taint.Sinks
+	extraSanitizers taint.Sanitizers
+)
+
+func init() {
+	taint.RegisterFlags(&Analyzer.Flags, &extraSources, &extraSinks, &extraSanitizers)
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	// Require the os package is imported in the program being analyzed
+	// before running the analysis, since the secrets come from it.
+	//
+	// This prevents wasting time analyzing programs without secrets.
+	if len(extraSources) == 0 && !taint.Imports(pass.Pkg, "os") {
+		return nil, nil
+	}
+
+	// Get the built SSA IR.
+	buildSSA := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+
+	// Identify the main function from the package's SSA IR.
+	mainFn := buildSSA.Pkg.Func("main")
+	if mainFn == nil {
+		return nil, nil
+	}
+
+	// Construct a callgraph, using the main function as the root,
+	// constructed of all other functions. This returns a callgraph
+	// we can use to identify directed paths to egress functions.
+	cg, err := callgraphutil.NewGraph(mainFn, buildSSA.SrcFuncs...)
+	if err != nil {
+		// Functions which panicked while being added to the callgraph
+		// are skipped, continuing with the rest of the program.
+		var panicErr *callgraphutil.PanicError
+		if !errors.As(err, &panicErr) {
+			return nil, fmt.Errorf("failed to create new callgraph: %w", err)
+		}
+	}
+
+	// Paths which panicked while being checked are skipped.
+	results, _ := Run(cg)
+
+	for _, result := range results {
+		pass.Reportf(result.SinkValue.Pos(), "%s", result.Message)
+	}
+
+	return nil, nil
+}
+
+// Run performs the analyzer's secret leak check on the given callgraph.
+func Run(cg *callgraph.Graph) (taint.Results, error) {
+	return RunContext(context.Background(), cg)
+}
+
+// RunContext is like Run, but stops checking once the given context is
+// canceled, returning the context's error.
+func RunContext(ctx context.Context, cg *callgraph.Graph) (taint.Results, error) {
+	// Run the secret leak rule for sensitive values (sources) ending
+	// up in egress functions (sinks), including any additional sources
+	// and sinks given using flags.
+	rule := taint.NewRule(
+		Rule.Name(),
+		Rule.Message(),
+		Rule.Sources().Union(extraSources),
+		Rule.Sinks().Union(extraSinks),
+		Rule.Sanitizers().Union(extraSanitizers),
+	)
+
+	return taint.RunContext(ctx, cg, rule)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+)
+
+func main() {
+	log.Println("starting with", os.Getenv("API_SECRET")) // want "potential secret leak"
+
+	token, ok := os.LookupEnv("GITHUB_TOKEN")
+	if ok {
+		log.Printf("using token %s", token) // want "potential secret leak"
+	}
+
+	// Non-sensitive environment variables can be logged.
+	log.Println("listening on", os.Getenv("PORT"))
+
+	http.HandleFunc("/debug", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(os.Getenv("DB_PASSWORD"))) // want "potential secret leak"
+	})
+
+	http.ListenAndServe(":"+os.Getenv("PORT"), nil)
+}
@@ -1,6 +1,12 @@
 package taint
 
 import (
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/callgraph"
 	"golang.org/x/tools/go/ssa"
 )
 
@@ -41,6 +47,49 @@ func (t stringSet) includes(str string) (string, bool) {
 	return str, ok
 }
 
+// String returns the strings in the set as a sorted, comma-separated
+// list, which implements flag.Value.
+func (t stringSet) String() string {
+	strs := make([]string, 0, len(t))
+	for str := range t {
+		strs = append(strs, str)
+	}
+	sort.Strings(strs)
+	return strings.Join(strs, ",")
+}
+
+// Set adds each of the comma-separated strings to the set, which implements
+// flag.Value, allowing sources, sinks, and sanitizers to be given as flags,
+// such as -sinks="(*example.com/db.Conn).Exec".
+func (t *stringSet) Set(value string) error {
+	if *t == nil {
+		*t = stringSet{}
+	}
+	for _, str := range strings.Split(value, ",") {
+		str = strings.TrimSpace(str)
+		if str == "" {
+			continue
+		}
+		(*t)[str] = struct{}{}
+	}
+	return nil
+}
+
+// Union returns a new set with the strings in the set, and in all
+// of the other given sets.
+func (t stringSet) Union(others ...stringSet) stringSet {
+	union := stringSet{}
+	for str := range t {
+		union[str] = struct{}{}
+	}
+	for _, other := range others {
+		for str := range other {
+			union[str] = struct{}{}
+		}
+	}
+	return union
+}
+
 // Sources are the types that are considered "sources" of
 // tainted data in the program.
 type Sources = stringSet
@@ -57,6 +106,58 @@ func NewSources(sourceTypes ...string) Sources {
 	return srcs
 }
 
+// argumentSource parses a call source restricted to calls whose first
+// argument is a constant string matching a pattern, given in the form
+// fn("pattern"), such as os.Getenv("*_SECRET"), returning the function
+// and pattern. Patterns use the syntax of path.Match, such as "*".
+func argumentSource(src string) (fn, pattern string, ok bool) {
+	open := strings.Index(src, "(\"")
+	if open <= 0 || !strings.HasSuffix(src, "\")") {
+		return "", "", false
+	}
+	pattern, err := strconv.Unquote(src[open+1 : len(src)-1])
+	if err != nil {
+		return "", "", false
+	}
+	return src[:open], pattern, true
+}
+
+// ProtoMessageSource can be included in Sources to consider any type
+// implementing the protobuf message interface a source, such as the
+// request messages of gRPC service methods, whose fields are all user
+// controlled. Messages are detected using their generated ProtoMessage
+// method, without depending on the protobuf module.
+const ProtoMessageSource = "google.golang.org/protobuf/proto.Message"
+
+// protoMessage returns true if the given type (or a pointer to it) has
+// the ProtoMessage method generated for protobuf messages.
+func protoMessage(t types.Type) bool {
+	for {
+		ptr, ok := t.Underlying().(*types.Pointer)
+		if !ok {
+			break
+		}
+		// Check the pointer itself, since messages have pointer receivers,
+		// then any further indirection, e.g. a **Request field address.
+		if hasProtoMessageMethod(t) {
+			return true
+		}
+		t = ptr.Elem()
+	}
+	return hasProtoMessageMethod(types.NewPointer(t))
+}
+
+// hasProtoMessageMethod returns true if the method set of the given type
+// includes a "ProtoMessage()" method.
+func hasProtoMessageMethod(t types.Type) bool {
+	sel := types.NewMethodSet(t).Lookup(nil, "ProtoMessage")
+	if sel == nil {
+		return false
+	}
+	sig, ok := sel.Type().(*types.Signature)
+	return ok && sig.Params().Len() == 0 && sig.Results().Len() == 0
+}
+
 // Sinks are the types that are considered "sinks" that
 // tainted data in the program may flow into.
 type Sinks = stringSet
@@ -72,3 +173,50 @@ func NewSinks(sinkTypes ...string) Sinks {
 
 	return snks
 }
+
+// SinkByInterfaceMethod returns the methods in the given callgraph named
+// methodName, whose receiver type implements the given interface, as sinks.
+// This allows a sink to be described once for any library, instead of
+// listing every implementation, such as any type with a Query method like
+// *database/sql.DB:
+//
+//	interface {
+//		Query(query string, args ...any) (*sql.Rows, error)
+//	}
+func SinkByInterfaceMethod(cg *callgraph.Graph, iface *types.Interface, methodName string) Sinks {
+	snks := Sinks{}
+
+	for fn := range cg.Nodes {
+		if fn == nil || fn.Name() != methodName {
+			continue
+		}
+
+		recv := fn.Signature.Recv()
+		if recv == nil {
+			continue
+		}
+
+		if types.Implements(recv.Type(), iface) {
+			snks[fn.String()] = struct{}{}
+		}
+	}
+
+	return snks
+}
+
+// Sanitizers are the functions that are considered to
+// "sanitize" tainted data, such that it is safe to flow
+// into a sink (e.g. html.EscapeString).
+type Sanitizers = stringSet
+
+// NewSanitizers returns a new Sanitizers set with the given
+// sanitizer functions.
+func NewSanitizers(sanitizerFns ...string) Sanitizers {
+	sans := Sanitizers{}
+
+	for _, san := range sanitizerFns {
+		sans[san] = struct{}{}
+	}
+
+	return sans
+}
@@ -0,0 +1,114 @@
+package injection
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// placeholders are the fmt verbs (optionally quoted) which can be
+// replaced by a query parameter placeholder.
+var placeholders = strings.NewReplacer(
+	"'%s'", "?",
+	"'%d'", "?",
+	"%s", "?",
+	"%d", "?",
+)
+
+// suggestedFixes returns a fix to convert a query built with fmt.Sprintf,
+// passed directly to the given query call, into a parameterized query:
+//
+//	db.Query(fmt.Sprintf("SELECT * FROM foo WHERE name='%s'", name))
+//
+// Becomes:
+//
+//	db.Query("SELECT * FROM foo WHERE name=?", name)
+//
+// Only the simple case of a single fmt.Sprintf call using %s and %d
+// verbs is handled, otherwise no fix is returned.
+func suggestedFixes(pass *analysis.Pass, query *ast.CallExpr) []analysis.SuggestedFix {
+	if query == nil {
+		return nil
+	}
+
+	for _, arg := range query.Args {
+		sprintf, ok := arg.(*ast.CallExpr)
+		if !ok || !isSprintf(pass, sprintf) || sprintf.Ellipsis.IsValid() || len(sprintf.Args) < 2 {
+			continue
+		}
+
+		lit, ok := sprintf.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return nil
+		}
+
+		// Every verb in the format must be replaced with a placeholder,
+		// one for each of the remaining arguments.
+		parameterized := placeholders.Replace(lit.Value)
+		if strings.Contains(parameterized, "%") || strings.Count(parameterized, "?")-strings.Count(lit.Value, "?") != len(sprintf.Args)-1 {
+			return nil
+		}
+
+		var buf bytes.Buffer
+		buf.WriteString(parameterized)
+		for _, arg := range sprintf.Args[1:] {
+			buf.WriteString(", ")
+			buf.WriteString(nodeString(pass.Fset, arg))
+		}
+
+		return []analysis.SuggestedFix{{
+			Message: "use a parameterized query",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     sprintf.Pos(),
+				End:     sprintf.End(),
+				NewText: buf.Bytes(),
+			}},
+		}}
+	}
+
+	return nil
+}
+
+// isSprintf returns true if the given call is to fmt.Sprintf.
+func isSprintf(pass *analysis.Pass, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	return ok && fn.FullName() == "fmt.Sprintf"
+}
+
+// queryCall returns the call expression in the pass's files with the
+// given left parenthesis position, which is the position of a SSA call.
+func queryCall(pass *analysis.Pass, lparen token.Pos) *ast.CallExpr {
+	var found *ast.CallExpr
+	for _, file := range pass.Files {
+		if file.Pos() > lparen || lparen > file.End() {
+			continue
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			if found != nil {
+				return false
+			}
+			if call, ok := n.(*ast.CallExpr); ok && call.Lparen == lparen {
+				found = call
+				return false
+			}
+			return true
+		})
+	}
+	return found
+}
+
+// nodeString returns the formatted source code of the given node.
+func nodeString(fset *token.FileSet, n ast.Node) string {
+	var buf bytes.Buffer
+	format.Node(&buf, fset, n)
+	return buf.String()
+}
@@ -0,0 +1,111 @@
+package injection
+
+import (
+	"go/types"
+
+	"github.com/picatz/taint"
+	"golang.org/x/tools/go/ssa"
+)
+
+// gorqliteSQLMethods are the injectable gorqlite connection methods, mapped
+// to whether they're parameterized, taking a ParameterizedStatement (or a
+// slice of them) rather than raw SQL strings.
+//
+// https://github.com/rqlite/gorqlite#parameterized-queries
+var gorqliteSQLMethods = map[string]bool{
+	"(*github.com/rqlite/gorqlite.Connection).Query":                 false,
+	"(*github.com/rqlite/gorqlite.Connection).QueryOne":              false,
+	"(*github.com/rqlite/gorqlite.Connection).Write":                 false,
+	"(*github.com/rqlite/gorqlite.Connection).WriteOne":              false,
+	"(*github.com/rqlite/gorqlite.Connection).QueryParameterized":    true,
+	"(*github.com/rqlite/gorqlite.Connection).QueryOneParameterized": true,
+	"(*github.com/rqlite/gorqlite.Connection).WriteParameterized":    true,
+	"(*github.com/rqlite/gorqlite.Connection).WriteOneParameterized": true,
+}
+
+// gorqliteInjectable returns true if the given gorqlite connection call is
+// passed SQL which isn't a constant. Parameterized statements are only
+// injectable if their Query isn't a constant, since their Arguments are
+// sent separately from the SQL.
+func gorqliteInjectable(fn string, call *ssa.CallCommon) bool {
+	// Skip the connection receiver.
+	arg := call.Args[1]
+
+	var queries []ssa.Value
+	if gorqliteSQLMethods[fn] {
+		queries = statementQueries(arg, map[ssa.Value]bool{})
+	} else if _, ok := arg.(*ssa.Slice); ok {
+		for _, query := range taint.VariadicArgs(arg) {
+			queries = append(queries, query)
+		}
+	} else {
+		queries = []ssa.Value{arg}
+	}
+
+	if len(queries) == 0 {
+		// The statements were not built at the call site, so we can't
+		// tell if they're constant.
+		return true
+	}
+
+	for _, query := range queries {
+		if !constant(query, map[ssa.Value]bool{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// statementQueries returns the values stored in the Query field of the
+// given gorqlite.ParameterizedStatement value, or the elements of a slice
+// of them, e.g. []gorqlite.ParameterizedStatement{{Query: "..."}}.
+func statementQueries(v ssa.Value, visited map[ssa.Value]bool) []ssa.Value {
+	if visited[v] {
+		return nil
+	}
+	visited[v] = true
+
+	switch value := v.(type) {
+	case *ssa.UnOp:
+		return statementQueries(value.X, visited)
+	case *ssa.Slice:
+		return statementQueries(value.X, visited)
+	case *ssa.Alloc, *ssa.IndexAddr:
+		refs := value.Referrers()
+		if refs == nil {
+			return nil
+		}
+		var queries []ssa.Value
+		for _, ref := range *refs {
+			switch ref := ref.(type) {
+			case *ssa.IndexAddr:
+				queries = append(queries, statementQueries(ref, visited)...)
+			case *ssa.FieldAddr:
+				if fieldName(ref) != "Query" || ref.Referrers() == nil {
+					continue
+				}
+				for _, ref := range *ref.Referrers() {
+					if store, ok := ref.(*ssa.Store); ok {
+						queries = append(queries, store.Val)
+					}
+				}
+			}
+		}
+		return queries
+	}
+	return nil
+}
+
+// fieldName returns the name of the struct field addressed by the given
+// field address instruction.
+func fieldName(addr *ssa.FieldAddr) string {
+	ptr, ok := addr.X.Type().Underlying().(*types.Pointer)
+	if !ok {
+		return ""
+	}
+	st, ok := ptr.Elem().Underlying().(*types.Struct)
+	if !ok {
+		return ""
+	}
+	return st.Field(addr.Field).Name()
+}
@@ -0,0 +1,121 @@
+package injection
+
+import (
+	goconstant "go/constant"
+	"go/types"
+	"strings"
+
+	"github.com/picatz/taint"
+	"golang.org/x/tools/go/ssa"
+)
+
+// identifierKeywords are the SQL keywords which are followed by an
+// identifier, such as a table or column name, rather than a value.
+var identifierKeywords = map[string]struct{}{
+	"SELECT": {},
+	"FROM":   {},
+	"JOIN":   {},
+	"INTO":   {},
+	"UPDATE": {},
+	"TABLE":  {},
+	"BY":     {}, // ORDER BY, GROUP BY
+}
+
+// identifierQuery returns true if the given query value splices a value
+// which isn't a constant (or number) into an identifier position, such as
+// a table name after FROM. Identifiers can't be given as query parameters,
+// so these queries are injectable regardless of any placeholders used.
+//
+//	fmt.Sprintf("SELECT * FROM %s WHERE id = ?", table)
+//	"SELECT * FROM " + table
+func identifierQuery(v ssa.Value) bool {
+	switch v := v.(type) {
+	case *ssa.Call:
+		if v.Call.Value.String() != "fmt.Sprintf" || len(v.Call.Args) != 2 {
+			return false
+		}
+		format, ok := v.Call.Args[0].(*ssa.Const)
+		if !ok || format.Value == nil || format.Value.Kind() != goconstant.String {
+			return false
+		}
+		args := taint.VariadicArgs(v.Call.Args[1])
+		for i, prefix := range formatVerbPrefixes(goconstant.StringVal(format.Value)) {
+			arg, ok := args[int64(i)]
+			if ok && identifierPosition(prefix) && !safeArg(arg) {
+				return true
+			}
+		}
+	case *ssa.BinOp:
+		// The query is concatenated, so the left side is everything which
+		// precedes the right side, e.g. ("SELECT * FROM " + table).
+		prefix, ok := v.X.(*ssa.Const)
+		if !ok || prefix.Value == nil || prefix.Value.Kind() != goconstant.String {
+			return identifierQuery(v.X)
+		}
+		return identifierPosition(goconstant.StringVal(prefix.Value)) && !safeArg(v.Y)
+	}
+	return false
+}
+
+// safeArg returns true if the given formatted argument is a constant,
+// or a number (or bool), which can't carry SQL.
+func safeArg(arg ssa.Value) bool {
+	if mi, ok := arg.(*ssa.MakeInterface); ok {
+		arg = mi.X
+	}
+	if constant(arg, map[ssa.Value]bool{}) {
+		return true
+	}
+	basic, ok := arg.Type().Underlying().(*types.Basic)
+	return ok && basic.Info()&(types.IsNumeric|types.IsBoolean) != 0
+}
+
+// formatVerbPrefixes returns the text preceding each verb in the given
+// format string, in order, such that the i-th prefix belongs to the i-th
+// argument. Formats using explicit argument indexes (e.g. %[1]s) return
+// nil, since the verbs don't map to the arguments in order.
+func formatVerbPrefixes(format string) []string {
+	var prefixes []string
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		start := i
+		i++
+		// Skip flags, width, and precision (e.g. %-10.2f).
+		for i < len(format) && strings.IndexByte("+-# 0123456789.*", format[i]) >= 0 {
+			i++
+		}
+		if i >= len(format) {
+			break
+		}
+		switch format[i] {
+		case '%':
+			continue
+		case '[':
+			return nil
+		}
+		prefixes = append(prefixes, format[:start])
+	}
+	return prefixes
+}
+
+// identifierPosition returns true if a value following the given query
+// prefix is spliced in as an identifier, outside of any quotes, directly
+// following an identifier keyword (e.g. "SELECT * FROM ").
+func identifierPosition(prefix string) bool {
+	if strings.Count(prefix, "'")%2 != 0 || strings.Count(prefix, `"`)%2 != 0 {
+		// Within a quoted string value.
+		return false
+	}
+	if !strings.HasSuffix(prefix, " ") {
+		// Part of another token, e.g. "users_%s" or "= '%s'".
+		return false
+	}
+	fields := strings.Fields(prefix)
+	if len(fields) == 0 {
+		return false
+	}
+	_, ok := identifierKeywords[strings.ToUpper(fields[len(fields)-1])]
+	return ok
+}
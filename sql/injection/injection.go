@@ -1,7 +1,11 @@
 package injection
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"go/token"
+	"go/types"
 	"strings"
 
 	"github.com/picatz/taint"
@@ -9,6 +13,7 @@ import (
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/callgraph"
 	"golang.org/x/tools/go/ssa"
 )
 
@@ -35,10 +40,12 @@ var userControlledValues = taint.NewSources(
 	// Types (and fields)
 	"*net/http.Request",
 	//
+	// Protobuf messages, such as gRPC requests.
+	taint.ProtoMessageSource,
+	//
 	// "google.golang.org/grpc/metadata.MD", ?
 	//
 	// TODO: add more, consider pointer variants and specific fields on types
-	// TODO: consider support for protobuf defined *Request types...
 	// TODO: consider supprot for gRPC request metadata (HTTP2 headers)
 	// TODO: consider support for msgpack-rpc?
 )
@@ -49,10 +56,24 @@ var injectableSQLMethods = taint.NewSinks(
 	"(*database/sql.DB).QueryContext",
 	"(*database/sql.DB).QueryRow",
 	"(*database/sql.DB).QueryRowContext",
+	"(*database/sql.DB).Exec",
+	"(*database/sql.DB).ExecContext",
 	"(*database/sql.Tx).Query",
 	"(*database/sql.Tx).QueryContext",
 	"(*database/sql.Tx).QueryRow",
 	"(*database/sql.Tx).QueryRowContext",
+	"(*database/sql.Tx).Exec",
+	"(*database/sql.Tx).ExecContext",
+	"(*database/sql.Conn).QueryContext",
+	"(*database/sql.Conn).QueryRowContext",
+	"(*database/sql.Conn).ExecContext",
+	// Prepared statements are only safe if the statement text is not
+	// tainted, regardless of the parameters later given to the *Stmt.
+	"(*database/sql.DB).Prepare",
+	"(*database/sql.DB).PrepareContext",
+	"(*database/sql.Tx).Prepare",
+	"(*database/sql.Tx).PrepareContext",
+	"(*database/sql.Conn).PrepareContext",
 	// GORM v1
 	// https://gorm.io/docs/security.html
 	// https://gorm.io/docs/security.html#SQL-injection-Methods
@@ -68,10 +89,177 @@ var injectableSQLMethods = taint.NewSinks(
 	"(*github.com/jinzhu/gorm.DB).Raw",
 	"(*github.com/jinzhu/gorm.DB).Exec",
 	"(*github.com/jinzhu/gorm.DB).Order",
+	// GORM v2
+	// https://gorm.io/docs/security.html
+	"(*gorm.io/gorm.DB).Where",
+	"(*gorm.io/gorm.DB).Or",
+	"(*gorm.io/gorm.DB).Not",
+	"(*gorm.io/gorm.DB).Group",
+	"(*gorm.io/gorm.DB).Having",
+	"(*gorm.io/gorm.DB).Joins",
+	"(*gorm.io/gorm.DB).Select",
+	"(*gorm.io/gorm.DB).Distinct",
+	"(*gorm.io/gorm.DB).Pluck",
+	"(*gorm.io/gorm.DB).Raw",
+	"(*gorm.io/gorm.DB).Exec",
+	"(*gorm.io/gorm.DB).Order",
+	// go-pg v10
+	// https://pg.uptrace.dev/queries/
+	"(*github.com/go-pg/pg/v10.DB).Exec",
+	"(*github.com/go-pg/pg/v10.DB).Query",
+	"(*github.com/go-pg/pg/v10.DB).QueryOne",
+	// squirrel
+	// https://github.com/Masterminds/squirrel
+	"github.com/Masterminds/squirrel.Expr",
+	"(github.com/Masterminds/squirrel.SelectBuilder).Where",
+	"(github.com/Masterminds/squirrel.SelectBuilder).Having",
+	"(github.com/Masterminds/squirrel.SelectBuilder).GroupBy",
+	"(github.com/Masterminds/squirrel.SelectBuilder).OrderBy",
+	"(github.com/Masterminds/squirrel.UpdateBuilder).Where",
+	"(github.com/Masterminds/squirrel.UpdateBuilder).OrderBy",
+	"(github.com/Masterminds/squirrel.DeleteBuilder).Where",
+	"(github.com/Masterminds/squirrel.DeleteBuilder).OrderBy",
+	// xorm
+	// https://xorm.io/docs/chapter-05/readme/
+	"(*xorm.io/xorm.Engine).Query",
+	"(*xorm.io/xorm.Engine).Exec",
+	"(*xorm.io/xorm.Engine).Where",
+	"(*xorm.io/xorm.Engine).SQL",
+	"(*xorm.io/xorm.Session).Query",
+	"(*xorm.io/xorm.Session).Exec",
+	"(*xorm.io/xorm.Session).Where",
+	"(*xorm.io/xorm.Session).And",
+	"(*xorm.io/xorm.Session).Or",
+	"(*xorm.io/xorm.Session).SQL",
+	"(*xorm.io/xorm.Session).OrderBy",
+	"xorm.io/builder.Expr",
+	// gorqlite
+	// https://github.com/rqlite/gorqlite
+	"(*github.com/rqlite/gorqlite.Connection).Query",
+	"(*github.com/rqlite/gorqlite.Connection).QueryOne",
+	"(*github.com/rqlite/gorqlite.Connection).Write",
+	"(*github.com/rqlite/gorqlite.Connection).WriteOne",
+	"(*github.com/rqlite/gorqlite.Connection).QueryParameterized",
+	"(*github.com/rqlite/gorqlite.Connection).QueryOneParameterized",
+	"(*github.com/rqlite/gorqlite.Connection).WriteParameterized",
+	"(*github.com/rqlite/gorqlite.Connection).WriteOneParameterized",
+	// sqlx
+	// https://github.com/jmoiron/sqlx
+	//
+	// Named queries only bind the values of their named parameters
+	// (e.g. :name), so the query itself must still be a constant.
+	"(*github.com/jmoiron/sqlx.DB).Select",
+	"(*github.com/jmoiron/sqlx.DB).Get",
+	"(*github.com/jmoiron/sqlx.DB).MustExec",
+	"(*github.com/jmoiron/sqlx.DB).Queryx",
+	"(*github.com/jmoiron/sqlx.DB).QueryRowx",
+	"(*github.com/jmoiron/sqlx.DB).NamedExec",
+	"(*github.com/jmoiron/sqlx.DB).NamedQuery",
+	"(*github.com/jmoiron/sqlx.DB).NamedExecContext",
+	"(*github.com/jmoiron/sqlx.DB).NamedQueryContext",
+	"(*github.com/jmoiron/sqlx.Tx).NamedExec",
+	"(*github.com/jmoiron/sqlx.Tx).NamedQuery",
 	//
 	// TODO: add more, consider (non-)pointer variants?
 )
 
+// sanitizers convert user controlled values into types which can't carry
+// SQL, such as integers, making them safe to use in a query.
+var sanitizers = taint.NewSanitizers(
+	"strconv.Atoi",
+	"strconv.ParseBool",
+	"strconv.ParseFloat",
+	"strconv.ParseInt",
+	"strconv.ParseUint",
+)
+
+// modelSQLMethods are the injectable SQL methods which take a model
+// as the first argument, before the query.
+var modelSQLMethods = map[string]struct{}{
+	"(*github.com/go-pg/pg/v10.DB).Query":    {},
+	"(*github.com/go-pg/pg/v10.DB).QueryOne": {},
+	"(*github.com/jmoiron/sqlx.DB).Select":   {},
+	"(*github.com/jmoiron/sqlx.DB).Get":      {},
+}
+
+// variadicSQLMethods are the injectable SQL methods which take the query
+// as the first of their variadic arguments, followed by its parameters.
+var variadicSQLMethods = map[string]struct{}{
+	"(*xorm.io/xorm.Engine).Query":  {},
+	"(*xorm.io/xorm.Engine).Exec":   {},
+	"(*xorm.io/xorm.Session).Query": {},
+	"(*xorm.io/xorm.Session).Exec":  {},
+}
+
+// constant returns true if the given query value is a constant, or only
+// built from constants, e.g. placeholders concatenated within a loop.
+func constant(v ssa.Value, visited map[ssa.Value]bool) bool {
+	if visited[v] {
+		return true
+	}
+	visited[v] = true
+
+	switch v := v.(type) {
+	case *ssa.Const:
+		return true
+	case *ssa.BinOp:
+		return constant(v.X, visited) && constant(v.Y, visited)
+	case *ssa.Convert:
+		// Conversions of constants, such as string(json.RawMessage(`...`)),
+		// which aren't folded into a constant, since they're not strings.
+		return constant(v.X, visited)
+	case *ssa.ChangeType:
+		return constant(v.X, visited)
+	case *ssa.Phi:
+		for _, edge := range v.Edges {
+			if !constant(edge, visited) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// numericSprintf returns true if the given query value is built using
+// fmt.Sprintf with a constant format, where every argument is either a
+// constant, or a number (or bool), which can't carry SQL, regardless of
+// the verb used to format it (e.g. %d or %v). Any other argument, such
+// as a string formatted with %s, %q, or %v, is not safe.
+//
+//	fmt.Sprintf("SELECT * FROM users LIMIT %d", n)
+func numericSprintf(v ssa.Value) bool {
+	call, ok := v.(*ssa.Call)
+	if !ok || call.Call.Value.String() != "fmt.Sprintf" || len(call.Call.Args) != 2 {
+		return false
+	}
+
+	if _, ok := call.Call.Args[0].(*ssa.Const); !ok {
+		return false
+	}
+
+	args := taint.VariadicArgs(call.Call.Args[1])
+	if len(args) == 0 {
+		// The arguments were not built at the call site (e.g. args...).
+		return false
+	}
+
+	for _, arg := range args {
+		if mi, ok := arg.(*ssa.MakeInterface); ok {
+			arg = mi.X
+		}
+		if constant(arg, map[ssa.Value]bool{}) {
+			continue
+		}
+		basic, ok := arg.Type().Underlying().(*types.Basic)
+		if !ok || basic.Info()&(types.IsNumeric|types.IsBoolean) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Analyzer finds potential SQL injection issues to demonstrate
 // the github.com/picatz/taint package.
 var Analyzer = &analysis.Analyzer{
@@ -81,29 +269,36 @@ var Analyzer = &analysis.Analyzer{
 	Requires: []*analysis.Analyzer{buildssa.Analyzer},
 }
 
-// imports returns true if the package imports any of the given packages.
-func imports(pass *analysis.Pass, pkgs ...string) bool {
-	var imported bool
-	for _, imp := range pass.Pkg.Imports() {
-		for _, pkg := range pkgs {
-			if strings.HasSuffix(imp.Path(), pkg) {
-				imported = true
-				break
-			}
-		}
-		if imported {
-			break
-		}
-	}
-	return imported
+// extraSources, extraSinks, and extraSanitizers are set using the analyzer's flags.
+var (
+	extraSources    taint.Sources
+	extraSinks      taint.Sinks
+	extraSanitizers taint.Sanitizers
+)
+
+// storedValues are the sources of values read from the database, which
+// were possibly stored by a user, and are only checked in stored mode.
+var storedValues = taint.NewSources(
+	"(*database/sql.Rows).Scan",
+	"(*database/sql.Row).Scan",
+)
+
+// stored enables checking values read from the database (see storedValues)
+// used as identifiers in later queries, such as a table name, which is a
+// second-order SQL injection. Such values are often trusted, so it's opt-in.
+var stored bool
+
+func init() {
+	taint.RegisterFlags(&Analyzer.Flags, &extraSources, &extraSinks, &extraSanitizers)
+	Analyzer.Flags.BoolVar(&stored, "stored", false, "check values read from the database used as identifiers in later queries")
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
-	// Require the database/sql or GORM v1 packages are imported in the
-	// program being analyzed before running the analysis.
+	// Require the database/sql, GORM (v1 or v2), go-pg v10, squirrel, xorm, gorqlite or sqlx
+	// packages are imported in the program being analyzed before running the analysis.
 	//
 	// This prevents wasting time analyzing programs that don't use SQL.
-	if !imports(pass, "database/sql", "github.com/jinzhu/gorm") {
+	if len(extraSinks) == 0 && !taint.Imports(pass.Pkg, "database/sql", "github.com/jinzhu/gorm", "gorm.io/gorm", "github.com/go-pg/pg/v10", "github.com/Masterminds/squirrel", "xorm.io/xorm", "github.com/rqlite/gorqlite", "github.com/jmoiron/sqlx") {
 		return nil, nil
 	}
 
@@ -121,7 +316,12 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	// we can use to identify directed paths to SQL queries.
 	cg, err := callgraphutil.NewGraph(mainFn, buildSSA.SrcFuncs...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create new callgraph: %w", err)
+		// Functions which panicked while being added to the callgraph
+		// are skipped, continuing with the rest of the program.
+		var panicErr *callgraphutil.PanicError
+		if !errors.As(err, &panicErr) {
+			return nil, fmt.Errorf("failed to create new callgraph: %w", err)
+		}
 	}
 
 	// If you'd like to compare the callgraph constructed by the
@@ -155,38 +355,200 @@ func run(pass *analysis.Pass) (interface{}, error) {
 
 	// fmt.Println(callgraphutil.CallGraphString(cg))
 
+	// Report the findings, suggesting fixes for queries which can
+	// use placeholders instead.
+	//
+	// Each query is only reported once, even if it's reached by more than
+	// one path, such as calls through a struct field, which are linked to
+	// every function stored in that field (e.g. cmd.run(args)).
+	type diagnostic struct {
+		pos     token.Pos
+		message string
+	}
+	reported := map[diagnostic]struct{}{}
+
+	// Paths which panicked while being checked are skipped.
+	findings, _ := check(context.Background(), cg)
+
+	for _, f := range findings {
+		d := diagnostic{pos: f.SinkValue.Pos(), message: f.Message}
+		if _, ok := reported[d]; ok {
+			continue
+		}
+		reported[d] = struct{}{}
+
+		if !f.fixable {
+			pass.Reportf(f.SinkValue.Pos(), "%s", f.Message)
+			continue
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            f.SinkValue.Pos(),
+			Message:        f.Message,
+			SuggestedFixes: suggestedFixes(pass, queryCall(pass, f.SinkValue.Pos())),
+		})
+	}
+
+	return nil, nil
+}
+
+// Run performs the analyzer's SQL injection check on the given callgraph.
+func Run(cg *callgraph.Graph) (taint.Results, error) {
+	return RunContext(context.Background(), cg)
+}
+
+// RunContext is like Run, but stops checking once the given context is
+// canceled, returning the context's error.
+func RunContext(ctx context.Context, cg *callgraph.Graph) (taint.Results, error) {
+	findings, err := check(ctx, cg)
+
+	var results taint.Results
+	for _, f := range findings {
+		results = append(results, f.Result)
+	}
+	return results, err
+}
+
+// finding is a result reported by the analyzer, which is fixable if the
+// query can be rewritten to use placeholders (see suggestedFixes).
+type finding struct {
+	taint.Result
+	fixable bool
+}
+
+// check runs the taint check for SQL injection on the given callgraph,
+// then filters out the results which are safely parameterized, returning
+// any paths which panicked while being checked.
+func check(ctx context.Context, cg *callgraph.Graph) ([]finding, error) {
 	// Run taint check for user controlled values (sources) ending
-	// up in injectable SQL methods (sinks).
-	results := taint.Check(cg, userControlledValues, injectableSQLMethods)
+	// up in injectable SQL methods (sinks), unless sanitized.
+	sinks := injectableSQLMethods.Union(extraSinks)
+	opts := taint.Options{
+		Sanitizers: sanitizers.Union(extraSanitizers),
+	}
+
+	results, err := taint.CheckContextWithOptions(ctx, cg, userControlledValues.Union(extraSources), sinks, opts)
+
+	// Values read from the database are checked in their own pass, so
+	// they can only add second-order identifier findings, without hiding
+	// user controlled values used in the same query.
+	var storedResults taint.Results
+	if stored && err == nil {
+		storedResults, err = taint.CheckContextWithOptions(ctx, cg, storedValues, sinks, opts)
+	}
+
+	var findings []finding
+
+	// Query call sites already reported, to avoid reporting the same
+	// query twice when it mixes stored and user controlled values.
+	reported := map[ssa.CallInstruction]bool{}
+
+	report := func(result taint.Result, message string, fixable bool) {
+		result.Rule = "sqli"
+		result.Message = message
+		findings = append(findings, finding{Result: result, fixable: fixable})
+		reported[result.Path[len(result.Path)-1].Site] = true
+	}
 
 	// For each result, check if a prepared statement is providing
 	// a mitigation for the user controlled value.
 	//
 	// TODO: ensure this makes sense for all the GORM usage?
-	for _, result := range results {
+	for i, result := range append(results, storedResults...) {
 		// We found a query edge that is tainted by user input, is it
 		// doing this safely? We expect this to be safely done by
 		// providing a prepared statement as a constant in the query
 		// (first argument after context).
 		queryEdge := result.Path[len(result.Path)-1]
 
-		// Get the query arguments, skipping the first element, pointer to the DB.
-		queryArgs := queryEdge.Site.Common().Args[1:]
+		// Values read from the database are only reported when used as
+		// an identifier in a later query (see below), which wasn't
+		// already reported for a user controlled value.
+		storedValue := i >= len(results)
+		if storedValue && reported[queryEdge.Site] {
+			continue
+		}
+
+		// Query builders, such as squirrel, have their own rules for
+		// which arguments are raw SQL, and which are parameterized.
+		if _, ok := squirrelSQLMethods[queryEdge.Callee.Func.String()]; ok {
+			if !storedValue && squirrelInjectable(queryEdge.Callee.Func.String(), queryEdge.Site.Common()) {
+				report(result, "potential sql injection", false)
+			}
+			continue
+		}
+
+		// gorqlite takes either raw SQL strings, or parameterized statements.
+		if _, ok := gorqliteSQLMethods[queryEdge.Callee.Func.String()]; ok {
+			if !storedValue && gorqliteInjectable(queryEdge.Callee.Func.String(), queryEdge.Site.Common()) {
+				report(result, "potential sql injection", false)
+			}
+			continue
+		}
+
+		// Get the query arguments, skipping the first element, pointer to the DB,
+		// unless the query is given to a function, such as builder.Expr.
+		queryArgs := queryEdge.Site.Common().Args
+		if queryEdge.Callee.Func.Signature.Recv() != nil {
+			queryArgs = queryArgs[1:]
+		}
 
 		// Skip the context argument, if using a *Context query variant.
-		if strings.HasPrefix(queryEdge.Site.Value().Call.Value.String(), "Context") {
+		if strings.HasSuffix(queryEdge.Site.Value().Call.Value.String(), "Context") {
+			queryArgs = queryArgs[1:]
+		}
+
+		// Skip the model argument, if the query comes after it.
+		if _, ok := modelSQLMethods[queryEdge.Callee.Func.String()]; ok {
 			queryArgs = queryArgs[1:]
 		}
 
 		// Get the query function parameter.
 		query := queryArgs[0]
 
-		// Ensure it is a constant (prepared statement), otherwise report
-		// potential SQL injection.
-		if _, isConst := query.(*ssa.Const); !isConst {
-			pass.Reportf(result.SinkValue.Pos(), "potential sql injection")
+		// Get the query from the variadic arguments, e.g. xorm's sqlOrArgs.
+		if _, ok := variadicSQLMethods[queryEdge.Callee.Func.String()]; ok {
+			query, ok = taint.VariadicArgs(query)[0]
+			if !ok {
+				// The arguments were not built at the call site (e.g. args...).
+				if !storedValue {
+					report(result, "potential sql injection", false)
+				}
+				continue
+			}
+		}
+
+		// Unwrap queries passed as an interface{}, e.g. go-pg and GORM.
+		if mi, ok := query.(*ssa.MakeInterface); ok {
+			query = mi.X
+		}
+
+		// Conditions built using xorm's builder package are parameterized,
+		// other than raw SQL given to builder.Expr, which is checked itself.
+		if ci, ok := query.(*ssa.ChangeInterface); ok && ci.X.Type().String() == "xorm.io/builder.Cond" {
+			continue
+		}
+
+		// Identifiers, such as table names, can't be parameterized, so
+		// these are reported distinctly from other injectable queries.
+		if identifierQuery(query) {
+			if storedValue {
+				report(result, "potential second-order sql injection: stored SQL identifier", false)
+				continue
+			}
+			report(result, "potential sql injection: user controlled SQL identifier", false)
+			continue
+		}
+
+		if storedValue {
+			continue
+		}
+
+		// Ensure it is a constant (prepared statement), or only formats
+		// numbers into a constant, otherwise report potential SQL injection.
+		if !constant(query, map[ssa.Value]bool{}) && !numericSprintf(query) {
+			report(result, "potential sql injection", true)
 		}
 	}
 
-	return nil, nil
+	return findings, err
 }
@@ -1,9 +1,17 @@
 package injection
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/picatz/taint/callgraphutil"
 	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
 )
 
 var testdata = analysistest.TestData()
@@ -49,3 +57,218 @@ func TestExample(t *testing.T) {
 func TestI(t *testing.T) {
 	analysistest.Run(t, testdata, Analyzer, "i")
 }
+
+func TestSuggestedFixes(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "fix")
+}
+
+func TestPGV10(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "pgv10")
+}
+
+func TestGORMV2(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "gormv2")
+}
+
+func TestJ(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "j")
+}
+
+func TestK(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "k")
+}
+
+func TestL(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "l")
+}
+
+func TestM(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "m")
+}
+
+func TestSquirrel(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "squirrel")
+}
+
+func TestCtx(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "ctx")
+}
+
+func TestN(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "n")
+}
+
+func TestO(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "o")
+}
+
+func TestP(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "p")
+}
+
+func TestQ(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "q")
+}
+
+func TestGRPC(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "grpc")
+}
+
+func TestDecode(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "decode")
+}
+
+func TestSanitize(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "sanitize")
+}
+
+func TestSprintf(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "sprintf")
+}
+
+func TestPointer(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "pointer")
+}
+
+func TestBody(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "body")
+}
+
+func TestFlags(t *testing.T) {
+	if err := Analyzer.Flags.Set("sinks", "(*flags.client).Run"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { extraSinks = nil })
+
+	analysistest.Run(t, testdata, Analyzer, "flags")
+}
+
+func TestSanitizersFlag(t *testing.T) {
+	if err := Analyzer.Flags.Set("sanitizers", "myapp/validate.TableName"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { extraSanitizers = nil })
+
+	analysistest.Run(t, testdata, Analyzer, "customsanitizer")
+}
+
+func TestStored(t *testing.T) {
+	if err := Analyzer.Flags.Set("stored", "true"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { stored = false })
+
+	analysistest.Run(t, testdata, Analyzer, "stored")
+}
+
+func TestIdentifier(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "identifier")
+}
+
+func TestXormSession(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "xorm-session")
+}
+
+func TestGorqlite(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "gorqlite")
+}
+
+func TestReplace(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "replace")
+}
+
+func TestSqlxNamed(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "sqlx-named")
+}
+
+func TestConn(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "conn")
+}
+
+func TestCtxValue(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "ctxvalue")
+}
+
+func TestNamedString(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "namedstring")
+}
+
+func TestRawMessage(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "rawmessage")
+}
+
+func TestFieldFunc(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "fieldfunc")
+}
+
+func TestSlices(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "slices")
+}
+
+func TestRun(t *testing.T) {
+	dir, err := filepath.Abs(testdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load the testdata package in GOPATH mode, like analysistest.
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  filepath.Join(dir, "src", "identifier"),
+		Env:  append(os.Environ(), "GOPATH="+dir, "GO111MODULE=off", "GOPROXY=off"),
+	}, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prog, ssaPkgs := ssautil.Packages(pkgs, ssa.InstantiateGenerics)
+	prog.Build()
+
+	var srcFns []*ssa.Function
+	for fn := range ssautil.AllFunctions(prog) {
+		if fn.Pkg == ssaPkgs[0] {
+			srcFns = append(srcFns, fn)
+		}
+	}
+
+	cg, err := callgraphutil.NewGraph(ssaPkgs[0].Func("main"), srcFns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := Run(cg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same findings as the "want" comments in the testdata.
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d: %v", len(results), results)
+	}
+
+	var identifiers int
+	for _, result := range results {
+		if result.Rule != "sqli" {
+			t.Errorf("expected rule %q, got %q", "sqli", result.Rule)
+		}
+		if result.Message == "potential sql injection: user controlled SQL identifier" {
+			identifiers++
+		}
+	}
+
+	if identifiers != 3 {
+		t.Fatalf("expected 3 identifier results, got %d: %v", identifiers, results)
+	}
+
+	// Checking stops once the context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err = RunContext(ctx, cg)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the check to be canceled, got %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no results, got %d: %v", len(results), results)
+	}
+}
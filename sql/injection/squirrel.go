@@ -0,0 +1,67 @@
+package injection
+
+import (
+	"go/types"
+
+	"github.com/picatz/taint"
+	"golang.org/x/tools/go/ssa"
+)
+
+// squirrelSQLMethods are the injectable squirrel query builder methods,
+// which take raw SQL strings, mapped to whether they're variadic strings
+// (e.g. OrderBy) rather than a predicate (e.g. Where).
+//
+// https://github.com/Masterminds/squirrel#squirrel-is-not-an-orm
+var squirrelSQLMethods = map[string]bool{
+	"github.com/Masterminds/squirrel.Expr":                    false,
+	"(github.com/Masterminds/squirrel.SelectBuilder).Where":   false,
+	"(github.com/Masterminds/squirrel.SelectBuilder).Having":  false,
+	"(github.com/Masterminds/squirrel.SelectBuilder).GroupBy": true,
+	"(github.com/Masterminds/squirrel.SelectBuilder).OrderBy": true,
+	"(github.com/Masterminds/squirrel.UpdateBuilder).Where":   false,
+	"(github.com/Masterminds/squirrel.UpdateBuilder).OrderBy": true,
+	"(github.com/Masterminds/squirrel.DeleteBuilder).Where":   false,
+	"(github.com/Masterminds/squirrel.DeleteBuilder).OrderBy": true,
+}
+
+// squirrelInjectable returns true if the given squirrel builder call is
+// passed a raw SQL string which isn't a constant. Predicates that aren't
+// strings, such as sq.Eq{...}, are parameterized by squirrel, so they're
+// not injectable, even if they contain user controlled values.
+func squirrelInjectable(fn string, call *ssa.CallCommon) bool {
+	args := call.Args
+	if call.Signature().Recv() != nil {
+		// Skip the builder receiver.
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return false
+	}
+
+	if squirrelSQLMethods[fn] {
+		strs := taint.VariadicArgs(args[0])
+		if len(strs) == 0 {
+			// The strings were not built at the call site (e.g. strs...),
+			// so we can't tell if they're constant.
+			_, isConst := args[0].(*ssa.Const)
+			return !isConst
+		}
+		for _, str := range strs {
+			if !constant(str, map[ssa.Value]bool{}) {
+				return true
+			}
+		}
+		return false
+	}
+
+	pred := args[0]
+	if mi, ok := pred.(*ssa.MakeInterface); ok {
+		pred = mi.X
+	}
+
+	if basic, ok := pred.Type().Underlying().(*types.Basic); !ok || basic.Info()&types.IsString == 0 {
+		return false
+	}
+
+	return !constant(pred, map[ssa.Value]bool{})
+}
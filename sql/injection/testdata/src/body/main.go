@@ -0,0 +1,44 @@
+package main
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+)
+
+func readQuery(body io.Reader) string {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/read-all", func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			return
+		}
+		db.Query(string(b)) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/reader", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(readQuery(r.Body)) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/max-bytes", func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1024))
+		if err != nil {
+			return
+		}
+		db.Query(string(b)) // want "potential sql injection"
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/conn", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := db.Conn(r.Context())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.QueryContext(r.Context(), "SELECT * FROM users WHERE name = '"+r.FormValue("name")+"'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/conn-exec", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := db.Conn(r.Context())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.ExecContext(r.Context(), "DELETE FROM users WHERE name = '"+r.FormValue("name")+"'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/conn-prepare", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := db.Conn(r.Context())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.PrepareContext(r.Context(), "SELECT * FROM users WHERE name = '"+r.FormValue("name")+"'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/tx", func(w http.ResponseWriter, r *http.Request) {
+		tx, err := db.Begin()
+		if err != nil {
+			return
+		}
+		defer tx.Rollback()
+		tx.Query("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/tx-row", func(w http.ResponseWriter, r *http.Request) {
+		tx, err := db.Begin()
+		if err != nil {
+			return
+		}
+		defer tx.Rollback()
+		tx.QueryRow("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/safe-conn", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := db.Conn(r.Context())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.QueryContext(r.Context(), "SELECT * FROM users WHERE name = ?", r.FormValue("name"))
+	})
+
+	http.HandleFunc("/safe-tx", func(w http.ResponseWriter, r *http.Request) {
+		tx, err := db.Begin()
+		if err != nil {
+			return
+		}
+		defer tx.Rollback()
+		tx.Exec("DELETE FROM users WHERE name = ?", r.FormValue("name"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
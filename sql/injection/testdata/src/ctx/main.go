@@ -0,0 +1,44 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+		db.QueryContext(r.Context(), "SELECT * FROM users WHERE name = '"+r.FormValue("name")+"'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/query-row", func(w http.ResponseWriter, r *http.Request) {
+		db.QueryRowContext(r.Context(), "SELECT * FROM users WHERE name = '"+r.FormValue("name")+"'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/exec", func(w http.ResponseWriter, r *http.Request) {
+		db.ExecContext(r.Context(), "DELETE FROM users WHERE name = '"+r.FormValue("name")+"'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/tx", func(w http.ResponseWriter, r *http.Request) {
+		tx, err := db.BeginTx(r.Context(), nil)
+		if err != nil {
+			return
+		}
+		defer tx.Rollback()
+		tx.ExecContext(r.Context(), "DELETE FROM users WHERE name = '"+r.FormValue("name")+"'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		db.QueryContext(r.Context(), "SELECT * FROM users WHERE name = ?", r.FormValue("name"))
+	})
+
+	http.HandleFunc("/safe-exec", func(w http.ResponseWriter, r *http.Request) {
+		db.ExecContext(r.Context(), "DELETE FROM users WHERE name = ?", r.FormValue("name"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
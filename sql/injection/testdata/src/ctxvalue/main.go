@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+)
+
+type contextKey string
+
+const nameKey contextKey = "name"
+
+var db *sql.DB
+
+// findUser retrieves the user's name from the context, deep in the stack.
+func findUser(ctx context.Context) {
+	name := ctx.Value(nameKey).(string)
+	db.Query("SELECT * FROM users WHERE name = '" + name + "'") // want "potential sql injection"
+}
+
+// findTenant retrieves a name which was never stored in the context
+// from a request, so it's not user controlled.
+func findTenant(ctx context.Context) {
+	tenant, ok := ctx.Value(nameKey).(string)
+	if !ok {
+		return
+	}
+	db.Query("SELECT * FROM tenants WHERE name = '" + tenant + "'")
+}
+
+func main() {
+	var err error
+
+	db, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(context.Background(), nameKey, r.FormValue("name"))
+		findUser(ctx)
+	})
+
+	findTenant(context.WithValue(context.Background(), nameKey, "default"))
+
+	http.ListenAndServe(":8080", nil)
+}
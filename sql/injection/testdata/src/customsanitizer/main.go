@@ -0,0 +1,28 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"myapp/validate"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	// validate.TableName is only a sanitizer when given using the
+	// -sanitizers flag, since it's specific to this project.
+	http.HandleFunc("/sanitized", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM tbl_%s", validate.TableName(r.URL.Query().Get("table"))))
+	})
+
+	http.HandleFunc("/unsanitized", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM tbl_%s", r.URL.Query().Get("table"))) // want "potential sql injection"
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+type request struct {
+	Name  string `json:"name"`
+	Limit int    `json:"limit"`
+}
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/decode", func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return
+		}
+		db.Query("SELECT * FROM users WHERE name = '" + req.Name + "'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/unmarshal", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return
+		}
+		req := &request{}
+		if err := json.Unmarshal(body, req); err != nil {
+			return
+		}
+		db.Query("SELECT * FROM users WHERE name = '" + req.Name + "'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return
+		}
+		db.Query("SELECT * FROM users WHERE name = ?", req.Name)
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+var db *sql.DB
+
+// command is a named command, which is run using the function
+// stored in its run field.
+type command struct {
+	name string
+	run  func(args []string)
+}
+
+func login(db *sql.DB, pass string) {
+	db.Query("SELECT * FROM users WHERE pass = '" + pass + "'") // want "potential sql injection"
+}
+
+func search(name string) {
+	db.Query("SELECT * FROM users WHERE name = '" + name + "'") // want "potential sql injection"
+}
+
+// newLoginCommand returns a command storing a closure in its run field.
+func newLoginCommand(db *sql.DB) *command {
+	return &command{
+		name: "login",
+		run: func(args []string) {
+			pass := args[0]
+			login(db, pass)
+		},
+	}
+}
+
+// searchCommand is a command run by a package level function.
+func searchCommand(args []string) {
+	search(args[0])
+}
+
+// configure sets the run field, apart from where the command is created.
+func configure(cmd *command) {
+	cmd.run = searchCommand
+}
+
+// execute runs the given command, which is passed around by pointer.
+func execute(cmd *command, args []string) {
+	cmd.run(args)
+}
+
+func main() {
+	var err error
+
+	db, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		cmd := newLoginCommand(db)
+		execute(cmd, []string{r.FormValue("pass")})
+	})
+
+	http.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		cmd := &command{name: "search"}
+		configure(cmd)
+		execute(cmd, []string{r.FormValue("name")})
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
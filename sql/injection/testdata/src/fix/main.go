@@ -0,0 +1,25 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		rows, err := db.Query(fmt.Sprintf("SELECT * FROM foo WHERE name='%s' AND age=%d", r.FormValue("name"), len(r.FormValue("age")))) // want "potential sql injection"
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer rows.Close()
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
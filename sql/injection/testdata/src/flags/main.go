@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+)
+
+// client sends queries to a database without using database/sql, so its
+// query method isn't a known sink unless given using the -sinks flag.
+type client struct {
+	addr string
+}
+
+func (c *client) Run(query string) {}
+
+func main() {
+	c := &client{addr: "localhost:5432"}
+
+	http.HandleFunc("/run", func(w http.ResponseWriter, r *http.Request) {
+		c.Run(r.URL.Query().Get("query")) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		c.Run("SELECT 1")
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
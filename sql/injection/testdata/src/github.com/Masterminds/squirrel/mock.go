@@ -0,0 +1,71 @@
+package squirrel
+
+// Sqlizer is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/squirrel.go#L19
+type Sqlizer interface {
+	ToSql() (string, []interface{}, error)
+}
+
+// Eq is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/expr.go#L86
+type Eq map[string]interface{}
+
+// ToSql is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/expr.go#L181
+func (eq Eq) ToSql() (sql string, args []interface{}, err error) {
+	return "", nil, nil
+}
+
+type expr struct {
+	sql  string
+	args []interface{}
+}
+
+// ToSql is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/expr.go#L30
+func (e expr) ToSql() (sql string, args []interface{}, err error) {
+	return e.sql, e.args, nil
+}
+
+// Expr is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/expr.go#L26
+func Expr(sql string, args ...interface{}) Sqlizer {
+	return expr{sql: sql, args: args}
+}
+
+// SelectBuilder is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/select.go#L79
+type SelectBuilder struct {
+	parts []interface{}
+}
+
+// Select is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/statement.go#L89
+func Select(columns ...string) SelectBuilder {
+	return SelectBuilder{}
+}
+
+// From is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/select.go#L273
+func (b SelectBuilder) From(from string) SelectBuilder {
+	return b
+}
+
+// Where is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/select.go#L352
+func (b SelectBuilder) Where(pred interface{}, args ...interface{}) SelectBuilder {
+	b.parts = append(b.parts, pred)
+	b.parts = append(b.parts, args...)
+	return b
+}
+
+// GroupBy is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/select.go#L360
+func (b SelectBuilder) GroupBy(groupBys ...string) SelectBuilder {
+	return b
+}
+
+// Having is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/select.go#L367
+func (b SelectBuilder) Having(pred interface{}, rest ...interface{}) SelectBuilder {
+	return b
+}
+
+// OrderBy is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/select.go#L379
+func (b SelectBuilder) OrderBy(orderBys ...string) SelectBuilder {
+	return b
+}
+
+// ToSql is mocked from https://github.com/Masterminds/squirrel/blob/v1.5.4/select.go#L166
+func (b SelectBuilder) ToSql() (string, []interface{}, error) {
+	return "", nil, nil
+}
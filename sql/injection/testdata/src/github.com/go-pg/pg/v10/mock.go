@@ -0,0 +1,38 @@
+package pg
+
+// Options is mocked from https://github.com/go-pg/pg/blob/v10.11.1/options.go#L21
+type Options struct {
+	Addr     string
+	User     string
+	Password string
+	Database string
+}
+
+// DB is mocked from https://github.com/go-pg/pg/blob/v10.11.1/db.go#L32
+type DB struct{}
+
+// Result is mocked from https://github.com/go-pg/pg/blob/v10.11.1/result.go#L8
+type Result interface {
+	RowsAffected() int
+	RowsReturned() int
+}
+
+// Connect is mocked from https://github.com/go-pg/pg/blob/v10.11.1/db.go#L21
+func Connect(opt *Options) *DB {
+	return nil
+}
+
+// Exec is mocked from https://github.com/go-pg/pg/blob/v10.11.1/base.go#L235
+func (db *DB) Exec(query interface{}, params ...interface{}) (Result, error) {
+	return nil, nil
+}
+
+// Query is mocked from https://github.com/go-pg/pg/blob/v10.11.1/base.go#L289
+func (db *DB) Query(model, query interface{}, params ...interface{}) (Result, error) {
+	return nil, nil
+}
+
+// QueryOne is mocked from https://github.com/go-pg/pg/blob/v10.11.1/base.go#L264
+func (db *DB) QueryOne(model, query interface{}, params ...interface{}) (Result, error) {
+	return nil, nil
+}
@@ -0,0 +1,83 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DB is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+type DB struct{}
+
+// Tx is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+type Tx struct{}
+
+// Rows is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+type Rows struct{}
+
+// Row is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+type Row struct{}
+
+// Open is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+func Open(driverName, dataSourceName string) (*DB, error) {
+	return &DB{}, nil
+}
+
+// Select is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+func (db *DB) Select(dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+// Get is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+func (db *DB) Get(dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+// MustExec is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+func (db *DB) MustExec(query string, args ...interface{}) sql.Result {
+	return nil
+}
+
+// Queryx is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+func (db *DB) Queryx(query string, args ...interface{}) (*Rows, error) {
+	return &Rows{}, nil
+}
+
+// QueryRowx is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+func (db *DB) QueryRowx(query string, args ...interface{}) *Row {
+	return &Row{}
+}
+
+// NamedExec is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+func (db *DB) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+// NamedQuery is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+func (db *DB) NamedQuery(query string, arg interface{}) (*Rows, error) {
+	return &Rows{}, nil
+}
+
+// NamedExecContext is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx_context.go
+func (db *DB) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+// NamedQueryContext is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx_context.go
+func (db *DB) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*Rows, error) {
+	return &Rows{}, nil
+}
+
+// Beginx is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+func (db *DB) Beginx() (*Tx, error) {
+	return &Tx{}, nil
+}
+
+// NamedExec is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+func (tx *Tx) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+// NamedQuery is mocked from https://github.com/jmoiron/sqlx/blob/v1.3.5/sqlx.go
+func (tx *Tx) NamedQuery(query string, arg interface{}) (*Rows, error) {
+	return &Rows{}, nil
+}
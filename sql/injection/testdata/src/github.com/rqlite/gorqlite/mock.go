@@ -0,0 +1,61 @@
+package gorqlite
+
+// Connection is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/conn.go#L47
+type Connection struct{}
+
+// QueryResult is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/query.go#L223
+type QueryResult struct{}
+
+// WriteResult is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/write.go#L156
+type WriteResult struct{}
+
+// ParameterizedStatement is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/query.go#L125
+type ParameterizedStatement struct {
+	Query     string
+	Arguments []interface{}
+}
+
+// Open is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/api.go#L51
+func Open(connURL string) (*Connection, error) {
+	return &Connection{}, nil
+}
+
+// Query is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/query.go#L170
+func (conn *Connection) Query(sqlStatements []string) ([]QueryResult, error) {
+	return nil, nil
+}
+
+// QueryOne is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/query.go#L141
+func (conn *Connection) QueryOne(sqlStatement string) (QueryResult, error) {
+	return QueryResult{}, nil
+}
+
+// QueryParameterized is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/query.go#L190
+func (conn *Connection) QueryParameterized(sqlStatements []ParameterizedStatement) ([]QueryResult, error) {
+	return nil, nil
+}
+
+// QueryOneParameterized is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/query.go#L155
+func (conn *Connection) QueryOneParameterized(statement ParameterizedStatement) (QueryResult, error) {
+	return QueryResult{}, nil
+}
+
+// Write is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/write.go#L87
+func (conn *Connection) Write(sqlStatements []string) ([]WriteResult, error) {
+	return nil, nil
+}
+
+// WriteOne is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/write.go#L60
+func (conn *Connection) WriteOne(sqlStatement string) (WriteResult, error) {
+	return WriteResult{}, nil
+}
+
+// WriteParameterized is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/write.go#L104
+func (conn *Connection) WriteParameterized(sqlStatements []ParameterizedStatement) ([]WriteResult, error) {
+	return nil, nil
+}
+
+// WriteOneParameterized is mocked from https://github.com/rqlite/gorqlite/blob/v0.0.0-20230708021416-2acd02b70b79/write.go#L73
+func (conn *Connection) WriteOneParameterized(statement ParameterizedStatement) (WriteResult, error) {
+	return WriteResult{}, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type User struct {
+	ID   string
+	Name string
+}
+
+func main() {
+	db, _ := gorm.Open(nil, &gorm.Config{})
+
+	http.HandleFunc("/raw", func(w http.ResponseWriter, r *http.Request) {
+		var users []User
+		q := "SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'"
+		db.Raw(q).Scan(&users) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/where", func(w http.ResponseWriter, r *http.Request) {
+		var users []User
+		db.Where(r.FormValue("where")).Find(&users) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/exec", func(w http.ResponseWriter, r *http.Request) {
+		db.Exec("DELETE FROM users WHERE id = " + r.FormValue("id")) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/raw-safe", func(w http.ResponseWriter, r *http.Request) {
+		var user User
+		db.Raw("SELECT * FROM users WHERE id = ?", r.FormValue("id")).Scan(&user)
+	})
+
+	http.HandleFunc("/where-safe", func(w http.ResponseWriter, r *http.Request) {
+		var users []User
+		db.Where("name = ?", r.FormValue("name")).Find(&users)
+	})
+
+	http.HandleFunc("/exec-safe", func(w http.ResponseWriter, r *http.Request) {
+		db.Exec("DELETE FROM users WHERE id = ?", r.FormValue("id"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/rqlite/gorqlite"
+)
+
+func main() {
+	conn, err := gorqlite.Open("http://localhost:4001/")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/query-one", func(w http.ResponseWriter, r *http.Request) {
+		conn.QueryOne("SELECT * FROM users WHERE name = '" + r.URL.Query().Get("name") + "'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+		conn.Query([]string{"SELECT * FROM users WHERE name = '" + r.URL.Query().Get("name") + "'"}) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/write", func(w http.ResponseWriter, r *http.Request) {
+		q := "DELETE FROM users WHERE name = '" + r.URL.Query().Get("name") + "'"
+		conn.Write([]string{q}) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/write-one", func(w http.ResponseWriter, r *http.Request) {
+		conn.WriteOne("DELETE FROM users WHERE name = '" + r.URL.Query().Get("name") + "'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/write-one-parameterized", func(w http.ResponseWriter, r *http.Request) {
+		conn.WriteOneParameterized(gorqlite.ParameterizedStatement{ // want "potential sql injection"
+			Query: "DELETE FROM users WHERE name = '" + r.URL.Query().Get("name") + "'",
+		})
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		conn.QueryOneParameterized(gorqlite.ParameterizedStatement{
+			Query:     "SELECT * FROM users WHERE name = ?",
+			Arguments: []interface{}{r.URL.Query().Get("name")},
+		})
+	})
+
+	http.HandleFunc("/safe-slice", func(w http.ResponseWriter, r *http.Request) {
+		conn.WriteParameterized([]gorqlite.ParameterizedStatement{
+			{
+				Query:     "DELETE FROM users WHERE name = ?",
+				Arguments: []interface{}{r.URL.Query().Get("name")},
+			},
+		})
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+)
+
+// LookupRequest is similar to a message generated by protoc-gen-go, which
+// implements proto.Message using the generated ProtoMessage method.
+type LookupRequest struct {
+	Name  string
+	Table string
+	Limit int32
+}
+
+func (*LookupRequest) ProtoMessage()    {}
+func (*LookupRequest) Reset()           {}
+func (x *LookupRequest) String() string { return x.Name }
+
+type LookupResponse struct{}
+
+func (*LookupResponse) ProtoMessage()  {}
+func (*LookupResponse) Reset()         {}
+func (*LookupResponse) String() string { return "" }
+
+// options is not a protobuf message, so its fields are not sources.
+type options struct {
+	Table string
+}
+
+type server struct {
+	db *sql.DB
+}
+
+func (s *server) Lookup(ctx context.Context, req *LookupRequest) (*LookupResponse, error) {
+	s.db.QueryContext(ctx, "SELECT * FROM users WHERE name = '"+req.Name+"'") // want "potential sql injection"
+	return &LookupResponse{}, nil
+}
+
+func (s *server) LookupTable(ctx context.Context, req *LookupRequest) (*LookupResponse, error) {
+	s.db.QueryContext(ctx, "SELECT * FROM "+req.Table) // want "potential sql injection"
+	return &LookupResponse{}, nil
+}
+
+func (s *server) LookupLimit(ctx context.Context, req *LookupRequest) (*LookupResponse, error) {
+	s.db.QueryContext(ctx, "SELECT * FROM users LIMIT ?", req.Limit)
+	return &LookupResponse{}, nil
+}
+
+func (s *server) lookupOptions(ctx context.Context, opts *options) {
+	s.db.QueryContext(ctx, "SELECT * FROM "+opts.Table)
+}
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	srv := &server{db: db}
+
+	// In a real program, the gRPC server calls these methods with the
+	// requests it receives, which are entirely user controlled.
+	ctx := context.Background()
+	srv.Lookup(ctx, &LookupRequest{})
+	srv.LookupTable(ctx, &LookupRequest{})
+	srv.LookupLimit(ctx, &LookupRequest{})
+	srv.lookupOptions(ctx, &options{Table: "users"})
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/table", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM %s WHERE id = ?", r.URL.Query().Get("table")), r.URL.Query().Get("id")) // want "potential sql injection: user controlled SQL identifier"
+	})
+
+	http.HandleFunc("/order", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM users ORDER BY %s", r.URL.Query().Get("sort"))) // want "potential sql injection: user controlled SQL identifier"
+	})
+
+	http.HandleFunc("/concat", func(w http.ResponseWriter, r *http.Request) {
+		db.Query("SELECT * FROM " + r.URL.Query().Get("table")) // want "potential sql injection: user controlled SQL identifier"
+	})
+
+	http.HandleFunc("/value", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM users WHERE name = '%s'", r.URL.Query().Get("name"))) // want `potential sql injection$`
+	})
+
+	http.HandleFunc("/constant", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM %s WHERE id = ?", "users"), r.URL.Query().Get("id"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
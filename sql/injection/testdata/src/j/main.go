@@ -0,0 +1,60 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+func with(fn func()) {
+	fn()
+}
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/mutate", func(w http.ResponseWriter, r *http.Request) {
+		var query string
+
+		build := func() {
+			query = "SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'"
+		}
+		build()
+
+		db.Query(query) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/read", func(w http.ResponseWriter, r *http.Request) {
+		name := r.FormValue("name")
+		name = "'" + name + "'"
+
+		run := func() {
+			db.Query("SELECT * FROM users WHERE name = " + name) // want "potential sql injection"
+		}
+		run()
+	})
+
+	http.HandleFunc("/indirect", func(w http.ResponseWriter, r *http.Request) {
+		var query string
+		name := r.FormValue("name")
+
+		with(func() {
+			query = "SELECT * FROM users WHERE name = '" + name + "'"
+		})
+
+		db.Query(query) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/nested", func(w http.ResponseWriter, r *http.Request) {
+		name := r.FormValue("name")
+		func() {
+			func() {
+				db.Query("SELECT * FROM users WHERE name = " + name) // want "potential sql injection"
+			}()
+		}()
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/range", func(w http.ResponseWriter, r *http.Request) {
+		q := "SELECT * FROM users WHERE "
+		for _, p := range strings.Split(r.FormValue("filters"), ",") {
+			q += p + " AND "
+		}
+		db.Query(q) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/for", func(w http.ResponseWriter, r *http.Request) {
+		names := r.URL.Query()["name"]
+		q := "SELECT * FROM users WHERE name IN ("
+		for i := 0; i < len(names); i++ {
+			q += "'" + names[i] + "',"
+		}
+		q += ")"
+		db.Query(q) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		q := "SELECT * FROM users WHERE id IN ("
+		for i := 0; i < 3; i++ {
+			q += "?,"
+		}
+		q += ")"
+		db.Query(q, r.FormValue("a"), r.FormValue("b"), r.FormValue("c"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/last", func(w http.ResponseWriter, r *http.Request) {
+		clean := "users"
+		tainted := r.FormValue("name")
+		db.Query(fmt.Sprintf("SELECT * FROM %s WHERE name = '%s'", clean, tainted)) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/first", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM %s WHERE id = %d", r.FormValue("table"), 1)) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/unrelated", func(w http.ResponseWriter, r *http.Request) {
+		tainted := r.FormValue("name")
+		log.Printf("%s %s", "lookup", tainted)
+		db.Query(fmt.Sprintf("SELECT * FROM %s WHERE id = %d", "users", 1))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
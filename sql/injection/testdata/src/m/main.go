@@ -0,0 +1,51 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/tainted", func(w http.ResponseWriter, r *http.Request) {
+		stmt, err := db.Prepare("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'") // want "potential sql injection"
+		if err != nil {
+			return
+		}
+		defer stmt.Close()
+		stmt.Query()
+	})
+
+	http.HandleFunc("/tainted-context", func(w http.ResponseWriter, r *http.Request) {
+		stmt, err := db.PrepareContext(r.Context(), "SELECT * FROM "+r.FormValue("table")) // want "potential sql injection"
+		if err != nil {
+			return
+		}
+		defer stmt.Close()
+		stmt.Query()
+	})
+
+	http.HandleFunc("/params", func(w http.ResponseWriter, r *http.Request) {
+		stmt, err := db.Prepare("SELECT * FROM users WHERE name = ?")
+		if err != nil {
+			return
+		}
+		defer stmt.Close()
+		stmt.Query(r.FormValue("name"))
+	})
+
+	http.HandleFunc("/params-context", func(w http.ResponseWriter, r *http.Request) {
+		stmt, err := db.PrepareContext(r.Context(), "SELECT * FROM users WHERE name = ?")
+		if err != nil {
+			return
+		}
+		defer stmt.Close()
+		stmt.QueryContext(r.Context(), r.FormValue("name"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
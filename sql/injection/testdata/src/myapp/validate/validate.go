@@ -0,0 +1,14 @@
+package validate
+
+import "regexp"
+
+var tableNames = regexp.MustCompile(`^[a-z_]+$`)
+
+// TableName returns the given name if it is a valid table name,
+// or a default table name otherwise.
+func TableName(name string) string {
+	if !tableNames.MatchString(name) {
+		return "tbl_default"
+	}
+	return name
+}
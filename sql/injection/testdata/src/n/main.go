@@ -0,0 +1,38 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+type store struct {
+	*sql.DB
+}
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	s := &store{DB: db}
+
+	http.HandleFunc("/promoted", func(w http.ResponseWriter, r *http.Request) {
+		s.Query("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/method-value", func(w http.ResponseWriter, r *http.Request) {
+		query := s.Query
+		query("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/method-expression", func(w http.ResponseWriter, r *http.Request) {
+		(*store).Query(s, "SELECT * FROM users WHERE name = '"+r.FormValue("name")+"'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		s.Query("SELECT * FROM users WHERE name = ?", r.FormValue("name"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+// Query is a SQL query, which is converted to and from a plain string.
+type Query string
+
+// Name is a user's name.
+type Name string
+
+var db *sql.DB
+
+func run(q Query) {
+	db.Query(string(q)) // want "potential sql injection"
+}
+
+func main() {
+	var err error
+
+	db, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+		run(Query("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'"))
+	})
+
+	http.HandleFunc("/name", func(w http.ResponseWriter, r *http.Request) {
+		name := Name(r.FormValue("name"))
+		db.Query("SELECT * FROM users WHERE name = '" + string(name) + "'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/bytes", func(w http.ResponseWriter, r *http.Request) {
+		q := []byte("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'")
+		db.Query(string(Query(q))) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(string(Query("SELECT * FROM users WHERE name = ?")), r.FormValue("name"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
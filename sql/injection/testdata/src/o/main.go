@@ -0,0 +1,33 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+type server struct {
+	db *sql.DB
+}
+
+func (s *server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	s.db.Query(r.URL.Query().Get("q")) // want "potential sql injection"
+}
+
+func (s *server) handleSafe(w http.ResponseWriter, r *http.Request) {
+	s.db.Query("SELECT * FROM users WHERE name = ?", r.URL.Query().Get("name"))
+}
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	srv := &server{db: db}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/query", srv.handleQuery)
+	mux.HandleFunc("/safe", srv.handleSafe)
+
+	http.ListenAndServe(":8080", mux)
+}
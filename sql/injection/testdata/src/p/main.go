@@ -0,0 +1,25 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"sync"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	var once sync.Once
+
+	http.HandleFunc("/once", func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		once.Do(func() {
+			db.Query("SELECT * FROM users WHERE name = '" + name + "'") // want "potential sql injection"
+		})
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type User struct {
+	ID   int64
+	Name string
+}
+
+func main() {
+	db := pg.Connect(&pg.Options{
+		Addr: ":5432",
+	})
+
+	http.HandleFunc("/exec", func(w http.ResponseWriter, r *http.Request) {
+		db.Exec(fmt.Sprintf("DELETE FROM users WHERE name='%s'", r.FormValue("name"))) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+		var users []User
+		db.Query(&users, "SELECT * FROM users WHERE name = "+r.FormValue("name")) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/query-one", func(w http.ResponseWriter, r *http.Request) {
+		var user User
+		db.QueryOne(&user, "SELECT * FROM users WHERE id = "+r.URL.Query().Get("id")) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		var user User
+		db.QueryOne(&user, "SELECT * FROM users WHERE id = ?", r.URL.Query().Get("id"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+type query struct {
+	text *string
+}
+
+func set(p *string, r *http.Request) {
+	*p = "SELECT * FROM users WHERE name = '" + r.URL.Query().Get("name") + "'"
+}
+
+func get(p *string) string {
+	return *p
+}
+
+func run(db *sql.DB, p *string) {
+	db.Query(*p) // want "potential sql injection"
+}
+
+func runQuery(db *sql.DB, q query) {
+	db.Query(get(q.text)) // want "potential sql injection"
+}
+
+func runSafe(db *sql.DB, p *string) {
+	db.Query(*p)
+}
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/store", func(w http.ResponseWriter, r *http.Request) {
+		var text string
+		set(&text, r)
+		run(db, &text)
+	})
+
+	http.HandleFunc("/alias", func(w http.ResponseWriter, r *http.Request) {
+		text := new(string)
+		q := query{text: text}
+		set(text, r)
+		runQuery(db, q)
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		text := "SELECT * FROM users"
+		runSafe(db, &text)
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
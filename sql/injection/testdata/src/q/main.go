@@ -0,0 +1,36 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/sort", func(w http.ResponseWriter, r *http.Request) {
+		names := []string{"b", "a"}
+		order := r.URL.Query().Get("order")
+		sort.Slice(names, func(i, j int) bool {
+			db.Query("SELECT * FROM users ORDER BY " + order) // want "potential sql injection"
+			return names[i] < names[j]
+		})
+	})
+
+	http.HandleFunc("/sort-func", func(w http.ResponseWriter, r *http.Request) {
+		names := []string{"b", "a"}
+		order := r.URL.Query().Get("order")
+		slices.SortFunc(names, func(a, b string) int {
+			db.Query("SELECT * FROM users ORDER BY " + order) // want "potential sql injection"
+			return strings.Compare(a, b)
+		})
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+type request struct {
+	Filter json.RawMessage `json:"filter"`
+}
+
+var db *sql.DB
+
+func main() {
+	var err error
+
+	db, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/body", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return
+		}
+		rawMsg := json.RawMessage(body)
+		db.Query(string(rawMsg)) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/field", func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return
+		}
+		db.Query("SELECT * FROM users WHERE " + string(req.Filter)) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		rawMsg := json.RawMessage(`"SELECT * FROM users WHERE name = ?"`)
+		db.Query(string(rawMsg), r.FormValue("name"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
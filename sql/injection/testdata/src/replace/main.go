@@ -0,0 +1,40 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+const skeleton = "SELECT * FROM users WHERE id = '{id}'"
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/replace-all", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(strings.ReplaceAll(skeleton, "{id}", r.URL.Query().Get("id"))) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/replace", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(strings.Replace(skeleton, "{id}", r.URL.Query().Get("id"), 1)) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/replacer", func(w http.ResponseWriter, r *http.Request) {
+		replacer := strings.NewReplacer("{id}", r.URL.Query().Get("id"))
+		db.Query(replacer.Replace(skeleton)) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/map", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(strings.Map(unicode.ToUpper, r.URL.Query().Get("query"))) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/constant", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(strings.ReplaceAll(skeleton, "{id}", "1"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/atoi", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.URL.Query().Get("id"))
+		if err != nil {
+			return
+		}
+		db.Query(fmt.Sprintf("SELECT * FROM users WHERE id = %d", id))
+	})
+
+	http.HandleFunc("/parse-int", func(w http.ResponseWriter, r *http.Request) {
+		limit, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+		if err != nil {
+			return
+		}
+		db.Query("SELECT * FROM users LIMIT " + strconv.FormatInt(limit, 10))
+	})
+
+	// Only the limit is sanitized, not the name formatted with it.
+	http.HandleFunc("/partially-sanitized", func(w http.ResponseWriter, r *http.Request) {
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil {
+			return
+		}
+		db.Query(fmt.Sprintf("SELECT * FROM users WHERE name = '%s' LIMIT %d", r.URL.Query().Get("name"), limit)) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/unsanitized", func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		db.Query(fmt.Sprintf("SELECT * FROM users WHERE id = %s", id)) // want "potential sql injection"
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
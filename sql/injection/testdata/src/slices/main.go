@@ -0,0 +1,38 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/clone", func(w http.ResponseWriter, r *http.Request) {
+		names := []string{r.FormValue("name")}
+		cloned := slices.Clone(names)
+		db.Query("SELECT * FROM users WHERE name = '" + cloned[0] + "'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/concat", func(w http.ResponseWriter, r *http.Request) {
+		columns := slices.Concat([]string{"id"}, r.URL.Query()["column"])
+		db.Query("SELECT " + strings.Join(columns, ", ") + " FROM users") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/insert", func(w http.ResponseWriter, r *http.Request) {
+		columns := slices.Insert([]string{"id", "name"}, 1, r.FormValue("column"))
+		db.Query("SELECT " + columns[1] + " FROM users") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		columns := slices.Concat([]string{"id"}, []string{"name"})
+		db.Query("SELECT "+strings.Join(columns, ", ")+" FROM users WHERE name = ?", r.FormValue("name"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
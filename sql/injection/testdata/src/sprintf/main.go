@@ -0,0 +1,40 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/int", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM users LIMIT %d", len(r.URL.Query().Get("limit"))))
+	})
+
+	http.HandleFunc("/int64", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM uploads WHERE size > %d AND kind = '%s'", r.ContentLength, "image"))
+	})
+
+	http.HandleFunc("/float", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM items WHERE price < %f", float64(len(r.Form["price"]))))
+	})
+
+	http.HandleFunc("/string", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM users WHERE name = '%s'", r.URL.Query().Get("name"))) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/quoted", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM users WHERE name = %q", r.URL.Query().Get("name"))) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/mixed", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM users WHERE name = '%v' LIMIT %d", r.URL.Query().Get("name"), r.ContentLength)) // want "potential sql injection"
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type user struct {
+	Name string `db:"name"`
+}
+
+func main() {
+	db, err := sqlx.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/named-query", func(w http.ResponseWriter, r *http.Request) {
+		db.NamedQuery("SELECT * FROM users WHERE name = '"+r.URL.Query().Get("name")+"'", map[string]interface{}{}) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/named-exec", func(w http.ResponseWriter, r *http.Request) {
+		db.NamedExec("DELETE FROM users WHERE name = '"+r.URL.Query().Get("name")+"'", user{}) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/named-query-context", func(w http.ResponseWriter, r *http.Request) {
+		db.NamedQueryContext(context.Background(), "SELECT * FROM users WHERE name = '"+r.URL.Query().Get("name")+"'", user{}) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/tx-named-exec", func(w http.ResponseWriter, r *http.Request) {
+		tx, _ := db.Beginx()
+		tx.NamedExec("DELETE FROM users WHERE name = '"+r.URL.Query().Get("name")+"'", user{}) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/select", func(w http.ResponseWriter, r *http.Request) {
+		var users []user
+		db.Select(&users, "SELECT * FROM users WHERE name = '"+r.URL.Query().Get("name")+"'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/must-exec", func(w http.ResponseWriter, r *http.Request) {
+		db.MustExec("DELETE FROM users WHERE name = '" + r.URL.Query().Get("name") + "'") // want "potential sql injection"
+	})
+
+	// The named parameters are bound as values, so a constant query is
+	// safe, even when the parameters are tainted.
+	http.HandleFunc("/safe-named-query", func(w http.ResponseWriter, r *http.Request) {
+		db.NamedQuery("SELECT * FROM users WHERE name = :name", map[string]interface{}{"name": r.URL.Query().Get("name")})
+	})
+
+	http.HandleFunc("/safe-named-exec", func(w http.ResponseWriter, r *http.Request) {
+		db.NamedExec("DELETE FROM users WHERE name = :name", user{Name: r.URL.Query().Get("name")})
+	})
+
+	http.HandleFunc("/safe-get", func(w http.ResponseWriter, r *http.Request) {
+		var u user
+		db.Get(&u, "SELECT * FROM users WHERE name = ?", r.URL.Query().Get("name"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
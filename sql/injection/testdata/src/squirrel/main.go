@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net/http"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func main() {
+	http.HandleFunc("/order", func(w http.ResponseWriter, r *http.Request) {
+		sq.Select("*").From("users").OrderBy(r.FormValue("order")).ToSql() // want "potential sql injection"
+	})
+
+	http.HandleFunc("/group", func(w http.ResponseWriter, r *http.Request) {
+		sq.Select("*").From("users").GroupBy(r.FormValue("group")).ToSql() // want "potential sql injection"
+	})
+
+	http.HandleFunc("/where", func(w http.ResponseWriter, r *http.Request) {
+		sq.Select("*").From("users").Where("name = '" + r.FormValue("name") + "'").ToSql() // want "potential sql injection"
+	})
+
+	http.HandleFunc("/having", func(w http.ResponseWriter, r *http.Request) {
+		sq.Select("*").From("users").GroupBy("name").Having(r.FormValue("having")).ToSql() // want "potential sql injection"
+	})
+
+	http.HandleFunc("/expr", func(w http.ResponseWriter, r *http.Request) {
+		sq.Select("*").From("users").Where(sq.Expr(r.FormValue("expr"))).ToSql() // want "potential sql injection"
+	})
+
+	http.HandleFunc("/eq", func(w http.ResponseWriter, r *http.Request) {
+		sq.Select("*").From("users").Where(sq.Eq{"name": r.FormValue("name")}).OrderBy("name").ToSql()
+	})
+
+	http.HandleFunc("/placeholder", func(w http.ResponseWriter, r *http.Request) {
+		sq.Select("*").From("users").Where("name = ?", r.FormValue("name")).ToSql()
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	// Table names read from the database may have been stored by a user,
+	// so using them as identifiers in a later query is injectable.
+	http.HandleFunc("/tables", func(w http.ResponseWriter, r *http.Request) {
+		rows, err := db.Query("SELECT name FROM user_tables")
+		if err != nil {
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return
+			}
+			db.Query(fmt.Sprintf("SELECT * FROM %s", name)) // want "potential second-order sql injection: stored SQL identifier"
+		}
+	})
+
+	http.HandleFunc("/table", func(w http.ResponseWriter, r *http.Request) {
+		var table string
+		if err := db.QueryRow("SELECT name FROM user_tables WHERE id = 1").Scan(&table); err != nil {
+			return
+		}
+		db.Query("SELECT * FROM " + table) // want "potential second-order sql injection: stored SQL identifier"
+	})
+
+	// Stored values used as query values, instead of identifiers,
+	// aren't reported in stored mode.
+	http.HandleFunc("/value", func(w http.ResponseWriter, r *http.Request) {
+		var name string
+		if err := db.QueryRow("SELECT name FROM users WHERE id = 1").Scan(&name); err != nil {
+			return
+		}
+		db.Query("SELECT * FROM users WHERE name = '" + name + "'")
+	})
+
+	// Stored values used along with user controlled values are still
+	// reported as a (first-order) injection of the user controlled value.
+	http.HandleFunc("/mixed", func(w http.ResponseWriter, r *http.Request) {
+		var name string
+		if err := db.QueryRow("SELECT name FROM users WHERE id = 1").Scan(&name); err != nil {
+			return
+		}
+		db.Query("SELECT * FROM users WHERE a = '" + name + "' AND b = '" + r.FormValue("b") + "'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/mixed-table", func(w http.ResponseWriter, r *http.Request) {
+		var table string
+		if err := db.QueryRow("SELECT name FROM tables WHERE id = 1").Scan(&table); err != nil {
+			return
+		}
+		db.Query(fmt.Sprintf("SELECT %s FROM %s", r.URL.Query().Get("column"), table)) // want "potential sql injection: user controlled SQL identifier"
+	})
+
+	http.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		db.Query(fmt.Sprintf("SELECT * FROM %s", r.URL.Query().Get("table"))) // want "potential sql injection: user controlled SQL identifier"
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+type User struct {
+	ID   int64
+	Name string
+}
+
+func main() {
+	engine, err := xorm.NewEngine("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/session-where", func(w http.ResponseWriter, r *http.Request) {
+		session := engine.NewSession()
+		defer session.Close()
+
+		var users []User
+		session.Where("name = '" + r.URL.Query().Get("name") + "'").Find(&users) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/session-query", func(w http.ResponseWriter, r *http.Request) {
+		session := engine.NewSession()
+		defer session.Close()
+
+		session.Query("SELECT * FROM users WHERE name = '" + r.URL.Query().Get("name") + "'") // want "potential sql injection"
+	})
+
+	http.HandleFunc("/session-order", func(w http.ResponseWriter, r *http.Request) {
+		var users []User
+		engine.NewSession().OrderBy(r.URL.Query().Get("sort")).Find(&users) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/engine-sql", func(w http.ResponseWriter, r *http.Request) {
+		var users []User
+		engine.SQL("SELECT * FROM users WHERE name = '" + r.URL.Query().Get("name") + "'").Find(&users) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/builder-expr", func(w http.ResponseWriter, r *http.Request) {
+		var users []User
+		engine.NewSession().Where(builder.Expr("name = '" + r.URL.Query().Get("name") + "'")).Find(&users) // want "potential sql injection"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		session := engine.NewSession()
+		defer session.Close()
+
+		var users []User
+		session.Where("name = ?", r.URL.Query().Get("name")).Find(&users)
+		session.Query("SELECT * FROM users WHERE name = ?", r.URL.Query().Get("name"))
+		session.Where(builder.Expr("name = ?", r.URL.Query().Get("name"))).Find(&users)
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,20 @@
+package builder
+
+// Cond is mocked from https://gitea.com/xorm/builder/src/tag/v0.3.13/cond.go#L8
+type Cond interface {
+	IsValid() bool
+}
+
+type expr struct {
+	sql  string
+	args []interface{}
+}
+
+func (e expr) IsValid() bool {
+	return len(e.sql) > 0
+}
+
+// Expr is mocked from https://gitea.com/xorm/builder/src/tag/v0.3.13/cond_expr.go#L15
+func Expr(sql string, args ...interface{}) Cond {
+	return expr{sql, args}
+}
@@ -0,0 +1,82 @@
+package xorm
+
+// Engine is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/engine.go#L38
+type Engine struct{}
+
+// Session is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/session.go#L53
+type Session struct{}
+
+// NewEngine is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/interface.go#L122
+func NewEngine(driverName string, dataSourceName string) (*Engine, error) {
+	return &Engine{}, nil
+}
+
+// NewSession is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/engine.go#L325
+func (engine *Engine) NewSession() *Session {
+	return &Session{}
+}
+
+// Query is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/engine.go#L1308
+func (engine *Engine) Query(sqlOrArgs ...interface{}) ([]map[string][]byte, error) {
+	return nil, nil
+}
+
+// Exec is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/engine.go#L1301
+func (engine *Engine) Exec(sqlOrArgs ...interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+// Where is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/engine.go#L1082
+func (engine *Engine) Where(query interface{}, args ...interface{}) *Session {
+	return engine.NewSession().Where(query, args...)
+}
+
+// SQL is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/engine.go#L367
+func (engine *Engine) SQL(query interface{}, args ...interface{}) *Session {
+	return engine.NewSession().SQL(query, args...)
+}
+
+// Close is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/session.go#L111
+func (session *Session) Close() error {
+	return nil
+}
+
+// Query is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/session_query.go#L22
+func (session *Session) Query(sqlOrArgs ...interface{}) ([]map[string][]byte, error) {
+	return nil, nil
+}
+
+// Exec is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/session_raw.go#L186
+func (session *Session) Exec(sqlOrArgs ...interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+// Where is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/session_cond.go#L18
+func (session *Session) Where(query interface{}, args ...interface{}) *Session {
+	return session
+}
+
+// And is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/session_cond.go#L24
+func (session *Session) And(query interface{}, args ...interface{}) *Session {
+	return session
+}
+
+// Or is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/session_cond.go#L30
+func (session *Session) Or(query interface{}, args ...interface{}) *Session {
+	return session
+}
+
+// SQL is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/session_raw.go#L31
+func (session *Session) SQL(query interface{}, args ...interface{}) *Session {
+	return session
+}
+
+// OrderBy is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/session_cols.go#L167
+func (session *Session) OrderBy(order interface{}, args ...interface{}) *Session {
+	return session
+}
+
+// Find is mocked from https://gitea.com/xorm/xorm/src/tag/v1.3.2/session_find.go#L29
+func (session *Session) Find(rowsSlicePtr interface{}, condiBean ...interface{}) error {
+	return nil
+}
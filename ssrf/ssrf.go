@@ -0,0 +1,128 @@
+package ssrf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/callgraphutil"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/callgraph"
+)
+
+var userControlledValues = taint.NewSources(
+	"*net/http.Request",
+)
+
+var requestFunctions = taint.NewSinks(
+	// Note: at this time, they *must* be a function or method.
+	"net/http.Get",
+	"net/http.Head",
+	"net/http.Post",
+	"net/http.PostForm",
+	"net/http.NewRequest",
+	"net/http.NewRequestWithContext",
+	"(*net/http.Client).Get",
+	"(*net/http.Client).Head",
+	"(*net/http.Client).Post",
+	"(*net/http.Client).PostForm",
+)
+
+// Rule is the taint rule for server-side request forgery, which can also
+// be run directly using taint.Run alongside other rules.
+//
+// Values derived from user input with url.Parse, (*url.URL).String and
+// similar remain tainted, so round-tripping a user controlled URL through
+// a *url.URL doesn't launder it.
+var Rule = taint.NewRule(
+	"ssrf",
+	"potential SSRF",
+	userControlledValues,
+	requestFunctions,
+	nil,
+)
+
+// Analyzer finds potential server-side request forgery (SSRF) issues.
+var Analyzer = &analysis.Analyzer{
+	Name:     "ssrf",
+	Doc:      "finds potential SSRF issues",
+	Run:      run,
+	Requires: []*analysis.Analyzer{buildssa.Analyzer},
+}
+
+// extraSources, extraSinks, and extraSanitizers are set using the analyzer's flags.
+var (
+	extraSources    taint.Sources
+	extraSinks      taint.Sinks
+	extraSanitizers taint.Sanitizers
+)
+
+func init() {
+	taint.RegisterFlags(&Analyzer.Flags, &extraSources, &extraSinks, &extraSanitizers)
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	// Require the net/http package is imported in the
+	// program being analyzed before running the analysis.
+	//
+	// This prevents wasting time analyzing programs that don't make requests.
+	if len(extraSinks) == 0 && !taint.Imports(pass.Pkg, "net/http") {
+		return nil, nil
+	}
+
+	// Get the built SSA IR.
+	buildSSA := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+
+	// Identify the main function from the package's SSA IR.
+	mainFn := buildSSA.Pkg.Func("main")
+	if mainFn == nil {
+		return nil, nil
+	}
+
+	// Construct a callgraph, using the main function as the root,
+	// constructed of all other functions. This returns a callgraph
+	// we can use to identify directed paths to outgoing requests.
+	cg, err := callgraphutil.NewGraph(mainFn, buildSSA.SrcFuncs...)
+	if err != nil {
+		// Functions which panicked while being added to the callgraph
+		// are skipped, continuing with the rest of the program.
+		var panicErr *callgraphutil.PanicError
+		if !errors.As(err, &panicErr) {
+			return nil, fmt.Errorf("failed to create new callgraph: %w", err)
+		}
+	}
+
+	// Paths which panicked while being checked are skipped.
+	results, _ := Run(cg)
+
+	for _, result := range results {
+		pass.Reportf(result.SinkValue.Pos(), "%s", result.Message)
+	}
+
+	return nil, nil
+}
+
+// Run performs the analyzer's SSRF check on the given callgraph.
+func Run(cg *callgraph.Graph) (taint.Results, error) {
+	return RunContext(context.Background(), cg)
+}
+
+// RunContext is like Run, but stops checking once the given context is
+// canceled, returning the context's error.
+func RunContext(ctx context.Context, cg *callgraph.Graph) (taint.Results, error) {
+	// Run the SSRF rule for user controlled values (sources)
+	// ending up in outgoing request functions (sinks),
+	// including any additional sources and sinks given using flags.
+	rule := taint.NewRule(
+		Rule.Name(),
+		Rule.Message(),
+		Rule.Sources().Union(extraSources),
+		Rule.Sinks().Union(extraSinks),
+		Rule.Sanitizers().Union(extraSanitizers),
+	)
+
+	return taint.RunContext(ctx, cg, rule)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+)
+
+func fetch(target string) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return
+	}
+	http.Get(u.String()) // want "potential SSRF"
+}
+
+func main() {
+	http.HandleFunc("/proxy", func(w http.ResponseWriter, r *http.Request) {
+		u, err := url.Parse(r.URL.Query().Get("url"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Get(u.String()) // want "potential SSRF"
+	})
+
+	http.HandleFunc("/fetch", func(w http.ResponseWriter, r *http.Request) {
+		fetch(r.FormValue("target"))
+	})
+
+	http.HandleFunc("/host", func(w http.ResponseWriter, r *http.Request) {
+		u := &url.URL{Scheme: "https", Host: r.FormValue("host"), Path: "/status"}
+		http.Get(u.String()) // want "potential SSRF"
+	})
+
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		u, _ := url.Parse("https://example.com/status")
+		http.Get(u.String())
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,100 @@
+package taint_test
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/picatz/taint/callgraphutil"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// loadSSA loads the main package in the given testdata directory, returning
+// its main function and all of the source functions of the loaded packages.
+func loadSSA(t *testing.T, name string) (*ssa.Function, []*ssa.Function) {
+	t.Helper()
+
+	loadMode :=
+		packages.NeedName |
+			packages.NeedDeps |
+			packages.NeedFiles |
+			packages.NeedModule |
+			packages.NeedTypes |
+			packages.NeedImports |
+			packages.NeedSyntax |
+			packages.NeedTypesInfo
+
+	parseMode := parser.SkipObjectResolution
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:    loadMode,
+		Context: context.Background(),
+		Env:     os.Environ(),
+		Dir:     filepath.Join("testdata", name),
+		Tests:   false,
+		ParseFile: func(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+			return parser.ParseFile(fset, filename, src, parseMode)
+		},
+	}, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ssaProg, ssaPkgs := ssautil.Packages(pkgs, ssa.InstantiateGenerics)
+
+	ssaProg.Build()
+
+	mainPkgs := ssautil.MainPackages(ssaPkgs)
+	if len(mainPkgs) == 0 {
+		t.Fatalf("no main package found in testdata/%s", name)
+	}
+
+	mainFn := mainPkgs[0].Func("main")
+	if mainFn == nil {
+		t.Fatalf("no main function found in testdata/%s", name)
+	}
+
+	var srcFns []*ssa.Function
+
+	for _, pkg := range ssaPkgs {
+		for _, member := range pkg.Members {
+			fn, ok := member.(*ssa.Function)
+			if !ok {
+				continue
+			}
+
+			var addAnons func(f *ssa.Function)
+			addAnons = func(f *ssa.Function) {
+				srcFns = append(srcFns, f)
+				for _, anon := range f.AnonFuncs {
+					addAnons(anon)
+				}
+			}
+			addAnons(fn)
+		}
+	}
+
+	return mainFn, srcFns
+}
+
+// loadCallGraph loads the main package in the given testdata directory,
+// returning a callgraph rooted at its main function.
+func loadCallGraph(t *testing.T, name string) *callgraph.Graph {
+	t.Helper()
+
+	mainFn, srcFns := loadSSA(t, name)
+
+	cg, err := callgraphutil.NewGraph(mainFn, srcFns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cg
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	htmltemplate "html/template"
+	"net/http"
+	"path/filepath"
+	"text/template"
+)
+
+func main() {
+	http.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		tmpl, err := template.ParseFiles(filepath.Join("templates", r.URL.Query().Get("page"))) // want "potential template path traversal"
+		if err != nil {
+			return
+		}
+		tmpl.Execute(w, nil)
+	})
+
+	http.HandleFunc("/theme", func(w http.ResponseWriter, r *http.Request) {
+		tmpl, err := htmltemplate.New("theme").ParseGlob(r.FormValue("theme") + "/*.html") // want "potential template path traversal"
+		if err != nil {
+			return
+		}
+		tmpl.Execute(w, nil)
+	})
+
+	// The template's path is constant, so executing it with user controlled
+	// data isn't a path traversal (content injection is a separate issue).
+	http.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		tmpl, err := template.ParseFiles("templates/hello.tmpl")
+		if err != nil {
+			return
+		}
+		tmpl.Execute(w, r.URL.Query().Get("name"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
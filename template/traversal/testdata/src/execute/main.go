@@ -0,0 +1,40 @@
+package main
+
+import (
+	"net/http"
+	"text/template"
+)
+
+// greeting is a constant template, which only refers to its data, so
+// executing it with user controlled data isn't a template injection, and
+// doesn't parse any files.
+var greeting = template.Must(template.New("greeting").Parse("Hello, {{.}}!\n"))
+
+func main() {
+	pages := template.Must(template.ParseGlob("templates/*.tmpl"))
+
+	http.HandleFunc("/greet", func(w http.ResponseWriter, r *http.Request) {
+		greeting.Execute(w, r.URL.Query().Get("name"))
+	})
+
+	http.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		pages.ExecuteTemplate(w, "page.tmpl", r.URL.Query())
+	})
+
+	http.HandleFunc("/inline", func(w http.ResponseWriter, r *http.Request) {
+		tmpl, err := template.New("inline").Parse("{{.Name}} ({{.Email}})")
+		if err != nil {
+			return
+		}
+		tmpl.Execute(w, map[string]string{
+			"Name":  r.FormValue("name"),
+			"Email": r.FormValue("email"),
+		})
+	})
+
+	http.HandleFunc("/files", func(w http.ResponseWriter, r *http.Request) {
+		template.ParseFiles(r.FormValue("file")) // want "potential template path traversal"
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
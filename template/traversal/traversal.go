@@ -0,0 +1,126 @@
+package traversal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/callgraphutil"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/callgraph"
+)
+
+var userControlledValues = taint.NewSources(
+	"*net/http.Request",
+)
+
+var templateParseFunctions = taint.NewSinks(
+	// Note: at this time, they *must* be a function or method.
+	"text/template.ParseFiles",
+	"text/template.ParseGlob",
+	"(*text/template.Template).ParseFiles",
+	"(*text/template.Template).ParseGlob",
+	"html/template.ParseFiles",
+	"html/template.ParseGlob",
+	"(*html/template.Template).ParseFiles",
+	"(*html/template.Template).ParseGlob",
+)
+
+// Rule is the taint rule for template path traversal, where user controlled
+// data is used as the path (or glob) of the template files to parse, which
+// can disclose the contents of other files, unlike template content injection
+// where the template's text itself is user controlled. It can also be run
+// directly using taint.Run alongside other rules.
+var Rule = taint.NewRule(
+	"tmplpath",
+	"potential template path traversal",
+	userControlledValues,
+	templateParseFunctions,
+	nil,
+)
+
+// Analyzer finds potential template path traversal issues.
+var Analyzer = &analysis.Analyzer{
+	Name:     "tmplpath",
+	Doc:      "finds potential template path traversal issues",
+	Run:      run,
+	Requires: []*analysis.Analyzer{buildssa.Analyzer},
+}
+
+// extraSources, extraSinks, and extraSanitizers are set using the analyzer's flags.
+var (
+	extraSources    taint.Sources
+	extraSinks      taint.Sinks
+	extraSanitizers taint.Sanitizers
+)
+
+func init() {
+	taint.RegisterFlags(&Analyzer.Flags, &extraSources, &extraSinks, &extraSanitizers)
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	// Require the text/template or html/template package is imported
+	// in the program being analyzed before running the analysis.
+	//
+	// This prevents wasting time analyzing programs that don't
+	// parse templates.
+	if len(extraSinks) == 0 && !taint.Imports(pass.Pkg, "text/template", "html/template") {
+		return nil, nil
+	}
+
+	// Get the built SSA IR.
+	buildSSA := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+
+	// Identify the main function from the package's SSA IR.
+	mainFn := buildSSA.Pkg.Func("main")
+	if mainFn == nil {
+		return nil, nil
+	}
+
+	// Construct a callgraph, using the main function as the root,
+	// constructed of all other functions. This returns a callgraph
+	// we can use to identify directed paths to template parsing.
+	cg, err := callgraphutil.NewGraph(mainFn, buildSSA.SrcFuncs...)
+	if err != nil {
+		// Functions which panicked while being added to the callgraph
+		// are skipped, continuing with the rest of the program.
+		var panicErr *callgraphutil.PanicError
+		if !errors.As(err, &panicErr) {
+			return nil, fmt.Errorf("failed to create new callgraph: %w", err)
+		}
+	}
+
+	// Paths which panicked while being checked are skipped.
+	results, _ := Run(cg)
+
+	for _, result := range results {
+		pass.Reportf(result.SinkValue.Pos(), "%s", result.Message)
+	}
+
+	return nil, nil
+}
+
+// Run performs the analyzer's template path traversal check on the given callgraph.
+func Run(cg *callgraph.Graph) (taint.Results, error) {
+	return RunContext(context.Background(), cg)
+}
+
+// RunContext is like Run, but stops checking once the given context is
+// canceled, returning the context's error.
+func RunContext(ctx context.Context, cg *callgraph.Graph) (taint.Results, error) {
+	// Run the template path traversal rule for user controlled values
+	// (sources) ending up in template parsing functions (sinks), including
+	// any additional sources and sinks given using flags.
+	rule := taint.NewRule(
+		Rule.Name(),
+		Rule.Message(),
+		Rule.Sources().Union(extraSources),
+		Rule.Sinks().Union(extraSinks),
+		Rule.Sanitizers().Union(extraSanitizers),
+	)
+
+	return taint.RunContext(ctx, cg, rule)
+}
@@ -0,0 +1,17 @@
+package traversal
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+var testdata = analysistest.TestData()
+
+func TestA(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "a")
+}
+
+func TestExecute(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "execute")
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+var db *sql.DB
+
+func search(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+
+	rows, err := db.Query("SELECT * FROM users WHERE name = '" + name + "'")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rows.Close()
+}
+
+func greet(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, "<h1>Hello, %s</h1>", r.URL.Query().Get("name"))
+}
+
+func audit(w http.ResponseWriter, r *http.Request) {
+	log.Printf("user %s visited", r.URL.Query().Get("name"))
+}
+
+func main() {
+	var err error
+	db, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/search", search)
+	http.HandleFunc("/greet", greet)
+	http.HandleFunc("/audit", audit)
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+var db *sql.DB
+
+func search(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query("SELECT * FROM users WHERE name = ?", r.URL.Query().Get("name"))
+	if err != nil {
+		http.Error(w, "search failed", http.StatusInternalServerError)
+		return
+	}
+	rows.Close()
+}
+
+func main() {
+	var err error
+
+	db, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/search", search)
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+// store searches for users using the function stored in its field, which
+// is called dynamically, so its findings have a lower confidence.
+type store struct {
+	search func(name string)
+}
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	s := &store{
+		search: func(name string) {
+			db.Query("SELECT * FROM users WHERE name = '" + name + "'")
+		},
+	}
+
+	http.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		s.search(r.FormValue("name"))
+	})
+
+	http.HandleFunc("/lookup", func(w http.ResponseWriter, r *http.Request) {
+		db.Query("SELECT * FROM users WHERE id = " + r.FormValue("id"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
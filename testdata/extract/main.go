@@ -0,0 +1,47 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+)
+
+var db *sql.DB
+
+// split returns a clean query, and the tainted user input.
+func split(r *http.Request) (string, string) {
+	return "SELECT * FROM users", r.URL.Query().Get("name")
+}
+
+// swap returns the given values in the opposite order.
+func swap(a, b string) (string, string) {
+	return strings.TrimSpace(b), a
+}
+
+func clean(w http.ResponseWriter, r *http.Request) {
+	query, _ := split(r)
+	db.Query(query)
+}
+
+func tainted(w http.ResponseWriter, r *http.Request) {
+	_, query := split(r)
+	db.Query(query)
+}
+
+func swapped(w http.ResponseWriter, r *http.Request) {
+	query, _ := swap(split(r))
+	db.Query(query)
+}
+
+func swappedClean(w http.ResponseWriter, r *http.Request) {
+	_, query := swap(split(r))
+	db.Query(query)
+}
+
+func main() {
+	http.HandleFunc("/clean", clean)
+	http.HandleFunc("/tainted", tainted)
+	http.HandleFunc("/swapped", swapped)
+	http.HandleFunc("/swapped-clean", swappedClean)
+	http.ListenAndServe(":8080", nil)
+}
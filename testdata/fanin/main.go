@@ -0,0 +1,39 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+var db *sql.DB
+
+// find is the single sink, which is reached from both handlers.
+func find(name string) {
+	rows, err := db.Query("SELECT * FROM users WHERE name = '" + name + "'")
+	if err != nil {
+		return
+	}
+	rows.Close()
+}
+
+func userHandler(w http.ResponseWriter, r *http.Request) {
+	find(r.URL.Query().Get("user"))
+}
+
+func adminHandler(w http.ResponseWriter, r *http.Request) {
+	find(r.URL.Query().Get("admin"))
+}
+
+func main() {
+	var err error
+
+	db, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/user", userHandler)
+	http.HandleFunc("/admin", adminHandler)
+
+	http.ListenAndServe(":8080", nil)
+}
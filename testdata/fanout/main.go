@@ -0,0 +1,45 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+var db *sql.DB
+
+// findUsers and findOrders each use the same (tainted) name in a SQL query.
+func findUsers(name string) {
+	rows, err := db.Query("SELECT * FROM users WHERE name = '" + name + "'")
+	if err != nil {
+		return
+	}
+	rows.Close()
+}
+
+func findOrders(name string) {
+	rows, err := db.Query("SELECT * FROM orders WHERE name = '" + name + "'")
+	if err != nil {
+		return
+	}
+	rows.Close()
+}
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+
+	findUsers(name)
+	findOrders(name)
+}
+
+func main() {
+	var err error
+
+	db, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/", handler)
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+// store implements the same Query method as *sql.DB, using a cache in
+// front of the database.
+type store struct {
+	db *sql.DB
+}
+
+func (s *store) Query(query string, args ...any) (*sql.Rows, error) {
+	return nil, nil
+}
+
+// Exec doesn't match the Query method name.
+func (s *store) Exec(query string, args ...any) (sql.Result, error) {
+	return nil, nil
+}
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	s := &store{db: db}
+
+	http.HandleFunc("/db", func(w http.ResponseWriter, r *http.Request) {
+		db.Query("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'")
+	})
+
+	http.HandleFunc("/store", func(w http.ResponseWriter, r *http.Request) {
+		s.Query("SELECT * FROM users WHERE name = '" + r.FormValue("name") + "'")
+	})
+
+	http.HandleFunc("/exec", func(w http.ResponseWriter, r *http.Request) {
+		s.Exec("DELETE FROM users WHERE name = '" + r.FormValue("name") + "'")
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
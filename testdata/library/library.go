@@ -0,0 +1,43 @@
+// Package library has no main function, so it is analyzed by using each
+// exported function as a root, with its string parameters as sources.
+package library
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Store is a user store.
+type Store struct {
+	db *sql.DB
+}
+
+// FindUser is vulnerable, since the name is controlled by the caller.
+func FindUser(db *sql.DB, name string) (*sql.Rows, error) {
+	return db.Query("SELECT * FROM users WHERE name = '" + name + "'")
+}
+
+// Delete is vulnerable, since the id is controlled by the caller.
+func (s *Store) Delete(id string) error {
+	_, err := s.db.Exec(fmt.Sprintf("DELETE FROM users WHERE id = '%s'", id))
+	return err
+}
+
+// Count is not vulnerable, since the query is a constant.
+func Count(db *sql.DB) (*sql.Rows, error) {
+	return db.Query("SELECT COUNT(*) FROM users")
+}
+
+// FindTable is not vulnerable, since the table is only given by trusted
+// callers, such as the library's own configuration.
+//
+//taint:trusted table
+func FindTable(db *sql.DB, table string) (*sql.Rows, error) {
+	return db.Query("SELECT * FROM " + table)
+}
+
+// findAdmin is not exported, so its parameter isn't a source, and it
+// is never called by an exported function.
+func findAdmin(db *sql.DB, name string) (*sql.Rows, error) {
+	return db.Query("SELECT * FROM admins WHERE name = '" + name + "'")
+}
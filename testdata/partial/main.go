@@ -0,0 +1,21 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/picatz/taint/testdata/partial/store"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		store.Find(db, r.URL.Query().Get("name"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
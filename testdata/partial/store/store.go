@@ -0,0 +1,14 @@
+package store
+
+import "database/sql"
+
+// Find queries the database for the given name.
+func Find(db *sql.DB, name string) {
+	db.Query("SELECT * FROM users WHERE name = '" + name + "'")
+}
+
+// Broken doesn't type check, so the package can't be built.
+func Broken() {
+	var n int = "not a number"
+	_ = n
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+var db *sql.DB
+
+// query recursively passes the (tainted) query through itself, before
+// eventually using it in a SQL query.
+func query(q string, depth int) {
+	if depth == 0 {
+		rows, err := db.Query(q)
+		if err != nil {
+			return
+		}
+		rows.Close()
+		return
+	}
+
+	query(q, depth-1)
+}
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	query(r.URL.Query().Get("q"), 3)
+}
+
+func main() {
+	var err error
+
+	db, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/", handler)
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"reflect"
+)
+
+var db *sql.DB
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	var name string
+	reflect.ValueOf(&name).Elem().SetString(r.URL.Query().Get("name"))
+
+	rows, err := db.Query("SELECT * FROM users WHERE name = '" + name + "'")
+	if err != nil {
+		return
+	}
+	rows.Close()
+}
+
+func main() {
+	var err error
+	db, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/", handler)
+	http.ListenAndServe(":8080", nil)
+}
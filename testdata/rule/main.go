@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Request is a stand-in for a request type from an internal framework.
+type Request struct {
+	params map[string]string
+}
+
+// Param returns the user controlled parameter with the given name.
+func (r *Request) Param(name string) string {
+	return r.params[name]
+}
+
+// Shell is a stand-in for a dangerous internal framework function.
+func Shell(cmd string) error {
+	fmt.Println(cmd)
+	return nil
+}
+
+func greet(r *Request) {
+	Shell("echo " + r.Param("name"))
+}
+
+func greetSafe(r *Request) {
+	Shell("echo " + strconv.Quote(r.Param("name")))
+}
+
+func main() {
+	r := &Request{
+		params: map[string]string{"name": "world"},
+	}
+
+	greet(r)
+	greetSafe(r)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+var db *sql.DB
+
+// search is given a name by its caller, which is also user input,
+// but found before the request given to search itself.
+func search(w http.ResponseWriter, r *http.Request, name string) {
+	rows, err := db.Query("SELECT * FROM users WHERE name = '" + name + "' AND q = '" + r.FormValue("q") + "'")
+	if err != nil {
+		return
+	}
+	rows.Close()
+}
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	search(w, r, r.FormValue("name"))
+}
+
+func main() {
+	var err error
+
+	db, err = sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	http.HandleFunc("/", handler)
+
+	http.ListenAndServe(":8080", nil)
+}
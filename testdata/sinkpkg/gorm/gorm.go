@@ -0,0 +1,21 @@
+// Package gorm is a stand-in for gorm.io/gorm, with a raw query method
+// which is a sink, but is in a different package than database/sql.
+package gorm
+
+import "database/sql"
+
+// DB wraps a database/sql database.
+type DB struct {
+	db *sql.DB
+}
+
+// Open returns a new DB for the given database.
+func Open(db *sql.DB) *DB {
+	return &DB{db: db}
+}
+
+// Raw runs the given raw SQL query.
+func (d *DB) Raw(query string) *DB {
+	d.db.Exec(query)
+	return d
+}
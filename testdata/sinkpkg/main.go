@@ -0,0 +1,27 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/picatz/taint/testdata/sinkpkg/gorm"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+
+	orm := gorm.Open(db)
+
+	http.HandleFunc("/sql", func(w http.ResponseWriter, r *http.Request) {
+		db.Query("SELECT * FROM users WHERE name = '" + r.URL.Query().Get("name") + "'")
+	})
+
+	http.HandleFunc("/gorm", func(w http.ResponseWriter, r *http.Request) {
+		orm.Raw("SELECT * FROM users WHERE name = '" + r.URL.Query().Get("name") + "'")
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -18,6 +18,10 @@ func WalkSSA(v ssa.Value, visit func(v ssa.Value) error) error {
 }
 
 func walkSSA(v ssa.Value, visit func(v ssa.Value) error, visited valueSet) error {
+	if v == nil {
+		return nil
+	}
+
 	if visited == nil {
 		visited = make(valueSet)
 	}
@@ -74,10 +78,22 @@ func walkSSA(v ssa.Value, visit func(v ssa.Value) error, visited valueSet) error
 		if err := walkSSA(v.X, visit, visited); err != nil {
 			return err
 		}
+	case *ssa.ChangeType:
+		if err := walkSSA(v.X, visit, visited); err != nil {
+			return err
+		}
 	case *ssa.MakeInterface:
 		if err := walkSSA(v.X, visit, visited); err != nil {
 			return err
 		}
+	case *ssa.TypeAssert:
+		if err := walkSSA(v.X, visit, visited); err != nil {
+			return err
+		}
+	case *ssa.Extract:
+		if err := walkSSA(v.Tuple, visit, visited); err != nil {
+			return err
+		}
 	case *ssa.Phi:
 		for _, edge := range v.Edges {
 			if err := walkSSA(edge, visit, visited); err != nil {
@@ -158,3 +174,39 @@ func walkSSA(v ssa.Value, visit func(v ssa.Value) error, visited valueSet) error
 
 	return nil
 }
+
+// VariadicArgs returns the values stored in the variadic arguments slice
+// for a call, indexed by their position, e.g. the args in Command(name, args...).
+// It's empty if the slice isn't allocated by the call, such as args... given
+// an existing slice.
+func VariadicArgs(v ssa.Value) map[int64]ssa.Value {
+	args := map[int64]ssa.Value{}
+
+	slice, ok := v.(*ssa.Slice)
+	if !ok {
+		return args
+	}
+
+	alloc, ok := slice.X.(*ssa.Alloc)
+	if !ok || alloc.Referrers() == nil {
+		return args
+	}
+
+	for _, ref := range *alloc.Referrers() {
+		idx, ok := ref.(*ssa.IndexAddr)
+		if !ok || idx.Referrers() == nil {
+			continue
+		}
+		i, ok := idx.Index.(*ssa.Const)
+		if !ok {
+			continue
+		}
+		for _, ref := range *idx.Referrers() {
+			if store, ok := ref.(*ssa.Store); ok && store.Addr == idx {
+				args[i.Int64()] = store.Val
+			}
+		}
+	}
+
+	return args
+}
@@ -0,0 +1,120 @@
+package taint
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+)
+
+// Warning describes a gap in the coverage of an analysis, such as a package
+// which couldn't be built, whose functions have no SSA body to analyze. Any
+// taint flowing through these functions is missed, so warnings should be
+// shown to users, to explain potential false negatives.
+type Warning struct {
+	// Package is the import path of the package the warning is about.
+	Package string
+
+	// Func is the function the warning is about, if any.
+	Func *ssa.Function
+
+	// Message describes the warning.
+	Message string
+}
+
+// String returns a string representation of the warning.
+func (w Warning) String() string {
+	if w.Func != nil {
+		return fmt.Sprintf("%v: %s", w.Func, w.Message)
+	}
+	return fmt.Sprintf("%s: %s", w.Package, w.Message)
+}
+
+// Warnings is a collection of warnings.
+type Warnings []Warning
+
+// Packages creates an SSA program for the given packages and their
+// dependencies, like ssautil.Packages, returning the SSA packages for each
+// of the given packages, along with warnings for any packages which have
+// errors, such as type errors.
+//
+// Unlike ssautil.Packages, packages with errors are still created using
+// their type information, without building their functions, so the rest
+// of the program can be analyzed, including the packages which import them.
+func Packages(pkgs []*packages.Package, mode ssa.BuilderMode) (*ssa.Program, []*ssa.Package, Warnings) {
+	var fset *token.FileSet
+	if len(pkgs) > 0 {
+		fset = pkgs[0].Fset
+	}
+	prog := ssa.NewProgram(fset, mode)
+
+	var warnings Warnings
+
+	created := map[*packages.Package]*ssa.Package{}
+
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		if p.Types == nil {
+			warnings = append(warnings, Warning{
+				Package: p.PkgPath,
+				Message: "package not analyzed: no type information",
+			})
+			return
+		}
+
+		// Packages which import a package with errors are marked as
+		// ill-typed, but can still be built if they have no errors.
+		files, info := p.Syntax, p.TypesInfo
+		if len(p.Errors) > 0 {
+			files, info = nil, nil
+			warnings = append(warnings, Warning{
+				Package: p.PkgPath,
+				Message: fmt.Sprintf("package not analyzed: %v", p.Errors[0]),
+			})
+		}
+
+		created[p] = prog.CreatePackage(p.Types, files, info, true)
+	})
+
+	ssaPkgs := make([]*ssa.Package, len(pkgs))
+	for i, pkg := range pkgs {
+		ssaPkgs[i] = created[pkg]
+	}
+
+	return prog, ssaPkgs, warnings
+}
+
+// Functions returns a warning for each function in the given callgraph
+// without an SSA body, which belongs to one of the packages warned about,
+// so it was referenced by the program, but couldn't be analyzed.
+func (w Warnings) Functions(cg *callgraph.Graph) Warnings {
+	warned := map[string]struct{}{}
+	for _, warning := range w {
+		warned[warning.Package] = struct{}{}
+	}
+
+	var warnings Warnings
+	for fn := range cg.Nodes {
+		// Functions without an object, such as package initializers,
+		// aren't written by users, so they're not worth warning about.
+		if fn == nil || fn.Pkg == nil || fn.Object() == nil || len(fn.Blocks) > 0 {
+			continue
+		}
+		if _, ok := warned[fn.Pkg.Pkg.Path()]; !ok {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Package: fn.Pkg.Pkg.Path(),
+			Func:    fn,
+			Message: "function not analyzed: no SSA body",
+		})
+	}
+
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i].Func.String() < warnings[j].Func.String()
+	})
+
+	return warnings
+}
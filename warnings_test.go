@@ -0,0 +1,74 @@
+package taint_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/picatz/taint"
+	"github.com/picatz/taint/callgraphutil"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+func TestPackagesWarnings(t *testing.T) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedDeps |
+			packages.NeedFiles |
+			packages.NeedModule |
+			packages.NeedTypes |
+			packages.NeedImports |
+			packages.NeedSyntax |
+			packages.NeedTypesInfo,
+		Context: context.Background(),
+		Env:     os.Environ(),
+		Dir:     filepath.Join("testdata", "partial"),
+	}, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prog, ssaPkgs, warnings := taint.Packages(pkgs, ssa.InstantiateGenerics)
+
+	prog.Build()
+
+	// The store package doesn't type check, so it is reported as skipped.
+	const store = "github.com/picatz/taint/testdata/partial/store"
+
+	if len(warnings) != 1 || warnings[0].Package != store {
+		t.Fatalf("expected 1 warning for %q, got %v", store, warnings)
+	}
+
+	// The main package imports the store package, but can still be built.
+	mainPkgs := ssautil.MainPackages(ssaPkgs)
+	if len(mainPkgs) == 0 {
+		t.Fatal("no main package found")
+	}
+
+	mainFn := mainPkgs[0].Func("main")
+	if mainFn == nil || len(mainFn.Blocks) == 0 {
+		t.Fatal("expected main function to be built")
+	}
+
+	var srcFns []*ssa.Function
+	for fn := range ssautil.AllFunctions(prog) {
+		if fn.Pkg == mainPkgs[0] {
+			srcFns = append(srcFns, fn)
+		}
+	}
+
+	cg, err := callgraphutil.NewGraph(mainFn, srcFns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The store.Find function called by main has no body to analyze.
+	fnWarnings := warnings.Functions(cg)
+
+	if len(fnWarnings) != 1 || fnWarnings[0].Func.String() != store+".Find" {
+		t.Fatalf("expected 1 warning for %s.Find, got %v", store, fnWarnings)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bufio"
+	"html"
+	"net/http"
+	"os"
+)
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+
+	func() {
+		bw := bufio.NewWriter(w)
+		bw.WriteString("<h1>Hello, " + name + "</h1>") // want "potential XSS"
+		bw.Flush()
+	}()
+
+	func() {
+		bw := bufio.NewWriterSize(w, 4096)
+		bw.Write([]byte(name)) // want "potential XSS"
+		bw.Flush()
+	}()
+
+	func() {
+		rw := bufio.NewReadWriter(bufio.NewReader(r.Body), bufio.NewWriter(w))
+		rw.WriteString(name) // want "potential XSS"
+		rw.Flush()
+	}()
+
+	// Escaped data is safe to write.
+	func() {
+		bw := bufio.NewWriter(w)
+		bw.WriteString(html.EscapeString(name))
+		bw.Flush()
+	}()
+
+	// Writes to anything other than the response are not relevant.
+	func() {
+		bw := bufio.NewWriter(os.Stdout)
+		bw.WriteString(name)
+		bw.Flush()
+	}()
+}
+
+func main() {
+	http.HandleFunc("/", handler)
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+)
+
+func main() {
+	http.HandleFunc("/fprintf", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<h1>Hello, %s</h1>", r.FormValue("name")) // want "potential XSS"
+	})
+
+	http.HandleFunc("/fprint", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.URL.Query().Get("q")) // want "potential XSS"
+	})
+
+	http.HandleFunc("/fprintln", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, r.Header.Get("Referer")) // want "potential XSS"
+	})
+
+	http.HandleFunc("/escaped", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<h1>Hello, %s</h1>", html.EscapeString(r.FormValue("name")))
+	})
+
+	http.HandleFunc("/partially-escaped", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", html.EscapeString(r.URL.Query().Get("a")), r.URL.Query().Get("b")) // want "potential XSS"
+	})
+
+	http.HandleFunc("/stdout", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(os.Stdout, "hello %s\n", r.FormValue("name"))
+	})
+
+	http.HandleFunc("/constant", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "<h1>Hello</h1>")
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
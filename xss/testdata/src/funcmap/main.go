@@ -0,0 +1,43 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// unsafeTmpl registers a function returning template.HTML, so any data
+// given to it, such as {{raw .}}, isn't escaped.
+var unsafeTmpl = template.Must(template.New("page").Funcs(template.FuncMap{
+	"raw": func(s string) template.HTML {
+		return template.HTML(s)
+	},
+}).Parse(`<p>{{raw .}}</p>`))
+
+// safeTmpl only registers functions returning plain strings, which
+// are escaped as usual.
+var safeTmpl = template.Must(template.New("page").Funcs(template.FuncMap{
+	"upper": strings.ToUpper,
+}).Parse(`<p>{{upper .}}</p>`))
+
+func main() {
+	http.HandleFunc("/raw", func(w http.ResponseWriter, r *http.Request) {
+		unsafeTmpl.Execute(w, r.URL.Query().Get("x")) // want "potential XSS"
+	})
+
+	http.HandleFunc("/local", func(w http.ResponseWriter, r *http.Request) {
+		tmpl, err := template.New("page").Funcs(template.FuncMap{
+			"url": func(s string) template.URL { return template.URL(s) },
+		}).Parse(`<a href="{{url .}}">link</a>`)
+		if err != nil {
+			return
+		}
+		tmpl.Execute(w, r.FormValue("next")) // want "potential XSS"
+	})
+
+	http.HandleFunc("/upper", func(w http.ResponseWriter, r *http.Request) {
+		safeTmpl.Execute(w, r.URL.Query().Get("x"))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
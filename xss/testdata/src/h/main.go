@@ -0,0 +1,32 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+)
+
+var tmpl = template.Must(template.New("page").Parse(`<p>{{.}}</p>`))
+
+func safe(w http.ResponseWriter, r *http.Request) {
+	// html/template contextually escapes the data, so this is safe.
+	err := tmpl.Execute(w, r.URL.Query().Get("input"))
+	if err != nil {
+		panic(err)
+	}
+}
+
+func unsafe(w http.ResponseWriter, r *http.Request) {
+	// Converting to template.HTML marks the data as trusted, which
+	// disables escaping for the user controlled value.
+	err := tmpl.Execute(w, template.HTML(r.URL.Query().Get("input"))) // want "potential XSS"
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	http.HandleFunc("/safe", safe)
+	http.HandleFunc("/unsafe", unsafe)
+
+	http.ListenAndServe(":8080", nil)
+}
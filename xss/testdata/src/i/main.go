@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io"
+	"net/http"
+)
+
+func echo(w io.Writer, r any) {
+	switch v := r.(type) {
+	case io.Reader:
+		b, err := io.ReadAll(v)
+		if err != nil {
+			panic(err)
+		}
+		w.Write(b)
+	case string:
+		w.Write([]byte(v))
+	}
+}
+
+func echoOK(w io.Writer, r any) {
+	ior, ok := r.(io.Reader)
+	if !ok {
+		return
+	}
+
+	b, err := io.ReadAll(ior)
+	if err != nil {
+		panic(err)
+	}
+
+	w.Write(b)
+}
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/switch":
+		echo(w, r.Body) // want "potential XSS"
+	case "/comma-ok":
+		echoOK(w, r.Body) // want "potential XSS"
+	}
+}
+
+func main() {
+	http.HandleFunc("/", handler)
+
+	http.ListenAndServe(":8080", nil)
+}
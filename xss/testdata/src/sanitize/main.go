@@ -0,0 +1,23 @@
+package main
+
+import (
+	"html"
+	"html/template"
+	"net/http"
+)
+
+func main() {
+	http.HandleFunc("/html", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(html.EscapeString(r.URL.Query().Get("input"))))
+	})
+
+	http.HandleFunc("/template", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(template.HTMLEscapeString(r.URL.Query().Get("input"))))
+	})
+
+	http.HandleFunc("/unsanitized", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Query().Get("input"))) // want "potential XSS"
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
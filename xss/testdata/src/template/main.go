@@ -0,0 +1,46 @@
+package main
+
+import (
+	"html"
+	"html/template"
+	"net/http"
+)
+
+var tmpl = template.Must(template.New("page").Parse(`<p>{{.}}</p>`))
+
+var banner = "<b>Welcome!</b>"
+
+type page struct {
+	Title string
+	Body  template.HTML
+}
+
+func main() {
+	http.HandleFunc("/html", func(w http.ResponseWriter, r *http.Request) {
+		tmpl.Execute(w, template.HTML(r.URL.Query().Get("x"))) // want "potential XSS"
+	})
+
+	http.HandleFunc("/url", func(w http.ResponseWriter, r *http.Request) {
+		tmpl.ExecuteTemplate(w, "page", template.URL(r.FormValue("next"))) // want "potential XSS"
+	})
+
+	http.HandleFunc("/field", func(w http.ResponseWriter, r *http.Request) {
+		tmpl.Execute(w, page{Title: "Hello", Body: template.HTML(r.FormValue("body"))}) // want "potential XSS"
+	})
+
+	http.HandleFunc("/input", func(w http.ResponseWriter, r *http.Request) {
+		userInput := r.URL.Query().Get("x")
+		tmpl.Execute(w, userInput)
+	})
+
+	http.HandleFunc("/escaped", func(w http.ResponseWriter, r *http.Request) {
+		tmpl.Execute(w, template.HTML(html.EscapeString(r.URL.Query().Get("x"))))
+	})
+
+	// The trusted HTML isn't user controlled, and the title is escaped.
+	http.HandleFunc("/trusted", func(w http.ResponseWriter, r *http.Request) {
+		tmpl.Execute(w, page{Title: r.FormValue("title"), Body: template.HTML(banner)})
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
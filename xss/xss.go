@@ -1,7 +1,10 @@
 package xss
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"go/types"
 	"strings"
 
 	"github.com/picatz/taint"
@@ -9,6 +12,7 @@ import (
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/callgraph"
 	"golang.org/x/tools/go/ssa"
 )
 
@@ -20,8 +24,232 @@ var injectableFunctions = taint.NewSinks(
 	// Note: at this time, they *must* be a function or method.
 	"(net/http.ResponseWriter).Write",
 	"(net/http.ResponseWriter).WriteHeader",
+	// html/template contextually escapes data, so these are only
+	// considered sinks when the escaping is bypassed (see below).
+	"(*html/template.Template).Execute",
+	"(*html/template.Template).ExecuteTemplate",
+	// fmt functions are only considered sinks when writing
+	// to a net/http.ResponseWriter (see below).
+	"fmt.Fprint",
+	"fmt.Fprintf",
+	"fmt.Fprintln",
 )
 
+// fprintFunctions write formatted values to the io.Writer given
+// as their first argument, which may not be a response.
+var fprintFunctions = map[string]struct{}{
+	"fmt.Fprint":   {},
+	"fmt.Fprintf":  {},
+	"fmt.Fprintln": {},
+}
+
+// responseWriter returns true if the given value is (or wraps) a
+// net/http.ResponseWriter, such as w in fmt.Fprintf(w, ...).
+func responseWriter(v ssa.Value) bool {
+	for {
+		switch value := v.(type) {
+		case *ssa.MakeInterface:
+			v = value.X
+		case *ssa.ChangeInterface:
+			v = value.X
+		default:
+			return v.Type().String() == "net/http.ResponseWriter"
+		}
+	}
+}
+
+// escapeFunctions sanitize user controlled values before they are written.
+var escapeFunctions = taint.NewSanitizers(
+	"html.EscapeString",
+	"html/template.HTMLEscapeString",
+	"text/template.HTMLEscapeString",
+)
+
+// escapeBypassTypes are html/template types which mark their content as
+// trusted, disabling the escaping that html/template would otherwise do.
+//
+// https://pkg.go.dev/html/template#hdr-Typed_Strings
+var escapeBypassTypes = map[string]struct{}{
+	"html/template.CSS":      {},
+	"html/template.HTML":     {},
+	"html/template.HTMLAttr": {},
+	"html/template.JS":       {},
+	"html/template.JSStr":    {},
+	"html/template.Srcset":   {},
+	"html/template.URL":      {},
+}
+
+// templateExecuteEdge returns the edge in the given path which calls an
+// html/template execution method, or nil if there isn't one.
+func templateExecuteEdge(path callgraphutil.Path) *callgraph.Edge {
+	for _, edge := range path {
+		if strings.HasPrefix(edge.Callee.Func.String(), "(*html/template.Template).Execute") {
+			return edge
+		}
+	}
+	return nil
+}
+
+// bypassesEscaping returns true if a user controlled value was converted
+// into one of the html/template typed strings, such as template.HTML, to
+// create the given value, including values stored in the fields or elements
+// of the template's data. The operand of each conversion is checked using
+// the given tainted function, so trusted (e.g. constant) content next to
+// other escaped values isn't reported.
+func bypassesEscaping(v ssa.Value, tainted func(ssa.Value) bool) bool {
+	return bypassed(v, tainted, map[ssa.Value]bool{})
+}
+
+func bypassed(v ssa.Value, tainted func(ssa.Value) bool, visited map[ssa.Value]bool) bool {
+	if v == nil || visited[v] {
+		return false
+	}
+	visited[v] = true
+
+	switch value := v.(type) {
+	case *ssa.ChangeType:
+		if _, ok := escapeBypassTypes[v.Type().String()]; ok {
+			return tainted(value.X)
+		}
+	case *ssa.Convert:
+		if _, ok := escapeBypassTypes[v.Type().String()]; ok {
+			return tainted(value.X)
+		}
+	case *ssa.Alloc, *ssa.FieldAddr, *ssa.IndexAddr:
+		// Follow the values stored to the address, or its fields and elements.
+		refs := value.Referrers()
+		if refs == nil {
+			return false
+		}
+		for _, ref := range *refs {
+			switch ref := ref.(type) {
+			case *ssa.Store:
+				if ref.Addr == v && bypassed(ref.Val, tainted, visited) {
+					return true
+				}
+			case *ssa.FieldAddr:
+				if ref.X == v && bypassed(ref, tainted, visited) {
+					return true
+				}
+			case *ssa.IndexAddr:
+				if ref.X == v && bypassed(ref, tainted, visited) {
+					return true
+				}
+			}
+		}
+		return false
+	case *ssa.Call, *ssa.Const, *ssa.Function, *ssa.Global, *ssa.Parameter, *ssa.FreeVar:
+		return false
+	}
+
+	instr, ok := v.(ssa.Instruction)
+	if !ok {
+		return false
+	}
+	for _, op := range instr.Operands(nil) {
+		if bypassed(*op, tainted, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// unsafeFuncMap returns true if the given html/template template was
+// configured with a function map (using Funcs) containing a function
+// which returns one of the typed strings bypassing escaping, such as
+// template.HTML. Any data executed with the template could be passed to
+// the function, so it isn't escaped.
+//
+//	template.New("page").Funcs(template.FuncMap{
+//		"raw": func(s string) template.HTML { return template.HTML(s) },
+//	})
+func unsafeFuncMap(tmpl ssa.Value) bool {
+	return unsafeTemplate(tmpl, map[ssa.Value]bool{})
+}
+
+func unsafeTemplate(v ssa.Value, visited map[ssa.Value]bool) bool {
+	if v == nil || visited[v] {
+		return false
+	}
+	visited[v] = true
+
+	switch value := v.(type) {
+	case *ssa.Call:
+		fn := value.Call.StaticCallee()
+		if fn == nil {
+			return false
+		}
+		name := fn.String()
+		if name == "(*html/template.Template).Funcs" && unsafeFuncs(value.Call.Args[1]) {
+			return true
+		}
+		// Follow the template through the methods returning it, such as
+		// template.New("page").Funcs(...).Parse(...), and template.Must.
+		if !strings.HasPrefix(name, "(*html/template.Template).") && name != "html/template.Must" {
+			return false
+		}
+		for _, arg := range value.Call.Args {
+			if unsafeTemplate(arg, visited) {
+				return true
+			}
+		}
+	case *ssa.Extract:
+		return unsafeTemplate(value.Tuple, visited)
+	case *ssa.UnOp:
+		// Templates stored in package level variables are assigned by
+		// the package's init function.
+		global, ok := value.X.(*ssa.Global)
+		if !ok || global.Pkg == nil {
+			return false
+		}
+		init := global.Pkg.Func("init")
+		if init == nil {
+			return false
+		}
+		for _, block := range init.Blocks {
+			for _, instr := range block.Instrs {
+				store, ok := instr.(*ssa.Store)
+				if ok && store.Addr == global && unsafeTemplate(store.Val, visited) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// unsafeFuncs returns true if the given html/template.FuncMap contains a
+// function which returns a typed string bypassing escaping.
+func unsafeFuncs(v ssa.Value) bool {
+	if ct, ok := v.(*ssa.ChangeType); ok {
+		v = ct.X
+	}
+	refs := v.Referrers()
+	if refs == nil {
+		return false
+	}
+	for _, ref := range *refs {
+		update, ok := ref.(*ssa.MapUpdate)
+		if !ok || update.Map != v {
+			continue
+		}
+		fn := update.Value
+		if mi, ok := fn.(*ssa.MakeInterface); ok {
+			fn = mi.X
+		}
+		sig, ok := fn.Type().Underlying().(*types.Signature)
+		if !ok {
+			continue
+		}
+		for i := 0; i < sig.Results().Len(); i++ {
+			if _, ok := escapeBypassTypes[sig.Results().At(i).Type().String()]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Analyzer finds potential XSS issues.
 var Analyzer = &analysis.Analyzer{
 	Name:     "xss",
@@ -30,21 +258,15 @@ var Analyzer = &analysis.Analyzer{
 	Requires: []*analysis.Analyzer{buildssa.Analyzer},
 }
 
-// imports returns true if the package imports any of the given packages.
-func imports(pass *analysis.Pass, pkgs ...string) bool {
-	var imported bool
-	for _, imp := range pass.Pkg.Imports() {
-		for _, pkg := range pkgs {
-			if strings.HasSuffix(imp.Path(), pkg) {
-				imported = true
-				break
-			}
-		}
-		if imported {
-			break
-		}
-	}
-	return imported
+// extraSources, extraSinks, and extraSanitizers are set using the analyzer's flags.
+var (
+	extraSources    taint.Sources
+	extraSinks      taint.Sinks
+	extraSanitizers taint.Sanitizers
+)
+
+func init() {
+	taint.RegisterFlags(&Analyzer.Flags, &extraSources, &extraSinks, &extraSanitizers)
 }
 
 func run(pass *analysis.Pass) (interface{}, error) {
@@ -52,7 +274,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	// program being analyzed before running the analysis.
 	//
 	// This prevents wasting time analyzing programs that don't log.
-	if !imports(pass, "net/http") {
+	if len(extraSinks) == 0 && !taint.Imports(pass.Pkg, "net/http") {
 		return nil, nil
 	}
 
@@ -70,42 +292,87 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	// we can use to identify directed paths to logging functions.
 	cg, err := callgraphutil.NewGraph(mainFn, buildSSA.SrcFuncs...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create new callgraph: %w", err)
+		// Functions which panicked while being added to the callgraph
+		// are skipped, continuing with the rest of the program.
+		var panicErr *callgraphutil.PanicError
+		if !errors.As(err, &panicErr) {
+			return nil, fmt.Errorf("failed to create new callgraph: %w", err)
+		}
 	}
 
 	// fmt.Println(cg)
 
+	// Paths which panicked while being checked are skipped.
+	results, _ := Run(cg)
+
+	for _, result := range results {
+		pass.Reportf(result.SinkValue.Pos(), "%s", result.Message)
+	}
+
+	return nil, nil
+}
+
+// Run performs the analyzer's XSS check on the given callgraph.
+func Run(cg *callgraph.Graph) (taint.Results, error) {
+	return RunContext(context.Background(), cg)
+}
+
+// RunContext is like Run, but stops checking once the given context is
+// canceled, returning the context's error.
+func RunContext(ctx context.Context, cg *callgraph.Graph) (taint.Results, error) {
 	// Run taint check for user controlled values (sources) ending
-	// up in injectable log functions (sinks).
-	results := taint.Check(cg, userControlledValues, injectableFunctions)
+	// up in injectable functions (sinks), which weren't escaped.
+	results, err := taint.CheckContextWithOptions(ctx, cg, userControlledValues.Union(extraSources), injectableFunctions.Union(extraSinks), taint.Options{
+		Sanitizers: escapeFunctions.Union(extraSanitizers),
+	})
+
+	var reported taint.Results
+
+	report := func(result taint.Result) {
+		result.Rule = "xss"
+		result.Message = "potential XSS"
+		reported = append(reported, result)
+	}
 
 	for _, result := range results {
-		// Check if html.EscapeString was called on the source value
-		// before it was passed to the sink.
-		var escaped bool
-		for _, edge := range result.Path {
-			for _, arg := range edge.Site.Common().Args {
-				taint.WalkSSA(arg, func(v ssa.Value) error {
-					call, ok := v.(*ssa.Call)
-					if !ok {
-						return nil
-					}
-					if call.Call.Value.String() == "html.EscapeString" {
-						escaped = true
-						return taint.ErrStopWalk
-					}
-					return nil
-				})
+		// Data executed with html/template is escaped, unless it was
+		// explicitly marked as trusted (e.g. template.HTML(input)). This
+		// includes writes to the response made by the template itself.
+		if edge := templateExecuteEdge(result.Path); edge != nil {
+			if result.SinkType != edge.Callee.String() {
+				// Reported using the template execution sink instead.
+				continue
+			}
+			// Conversions to typed strings are checked along the path
+			// up to the template execution.
+			path := result.Path
+			for i := range path {
+				if path[i] == edge {
+					path = path[:i+1]
+					break
+				}
 			}
-			if escaped {
-				break
+			tainted := func(v ssa.Value) bool {
+				return taint.Tainted(path, v, userControlledValues.Union(extraSources))
 			}
+
+			args := edge.Site.Common().Args
+			if bypassesEscaping(args[len(args)-1], tainted) || unsafeFuncMap(args[0]) {
+				report(result)
+			}
+			continue
 		}
 
-		if !escaped {
-			pass.Reportf(result.SinkValue.Pos(), "potential XSS")
+		// Writes using fmt to anything other than the response, such
+		// as os.Stdout, are not relevant.
+		if edge := result.Path.Last(); edge != nil && edge.Site != nil {
+			if _, ok := fprintFunctions[edge.Callee.Func.String()]; ok && !responseWriter(edge.Site.Common().Args[0]) {
+				continue
+			}
 		}
+
+		report(result)
 	}
 
-	return nil, nil
+	return reported, err
 }
@@ -1,9 +1,15 @@
 package xss
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/picatz/taint/callgraphutil"
 	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
 )
 
 var testdata = analysistest.TestData()
@@ -35,3 +41,75 @@ func TestF(t *testing.T) {
 func TestG(t *testing.T) {
 	analysistest.Run(t, testdata, Analyzer, "g")
 }
+
+func TestH(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "h")
+}
+
+func TestI(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "i")
+}
+
+func TestFprintf(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "fprintf")
+}
+
+func TestTemplate(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "template")
+}
+
+func TestSanitize(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "sanitize")
+}
+
+func TestFuncMap(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "funcmap")
+}
+
+func TestBufio(t *testing.T) {
+	analysistest.Run(t, testdata, Analyzer, "bufio")
+}
+
+func TestRunLibrary(t *testing.T) {
+	dir, err := filepath.Abs(testdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load the testdata package in GOPATH mode, like analysistest.
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  filepath.Join(dir, "src", "sanitize"),
+		Env:  append(os.Environ(), "GOPATH="+dir, "GO111MODULE=off", "GOPROXY=off"),
+	}, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prog, ssaPkgs := ssautil.Packages(pkgs, ssa.InstantiateGenerics)
+	prog.Build()
+
+	var srcFns []*ssa.Function
+	for fn := range ssautil.AllFunctions(prog) {
+		if fn.Pkg == ssaPkgs[0] {
+			srcFns = append(srcFns, fn)
+		}
+	}
+
+	// Every function is a root, like a library, so paths start with
+	// an edge from the synthetic root, which has no call site.
+	cg, err := callgraphutil.NewMultiRootGraph(srcFns, callgraphutil.GraphOptions{}, srcFns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := Run(cg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the unsanitized handler is reported.
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %v", len(results), results)
+	}
+}